@@ -775,6 +775,26 @@ func (f *FilterTree) hasVars() bool {
 	return false
 }
 
+// Clone returns a deep copy of the filter tree, so that the copy can be changed without
+// affecting the original, e.g. when the same filter is added to more than one query block.
+func (f *FilterTree) Clone() *FilterTree {
+	if f == nil {
+		return nil
+	}
+	ft := &FilterTree{Op: f.Op}
+	if f.Func != nil {
+		fn := *f.Func
+		fn.Args = append([]Arg(nil), f.Func.Args...)
+		fn.UID = append([]uint64(nil), f.Func.UID...)
+		fn.NeedsVar = append([]VarContext(nil), f.Func.NeedsVar...)
+		ft.Func = &fn
+	}
+	for _, fch := range f.Child {
+		ft.Child = append(ft.Child, fch.Clone())
+	}
+	return ft
+}
+
 // getVariablesAndQuery checks if the query has a variable list and stores it in
 // vmap. For variable list to be present, the query should have a name which is
 // also checked for. It also calls getQuery to create the GraphQuery object tree.
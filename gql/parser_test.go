@@ -5453,3 +5453,31 @@ func TestEmptyId(t *testing.T) {
 	_, err := Parse(r)
 	require.Error(t, err, "ID cannot be empty")
 }
+
+func TestFilterTreeClone(t *testing.T) {
+	query := `
+	{
+		me(func: uid(1)) @filter(anyofterms(name, "alice") AND (eq(age, "20") OR NOT has(friend))) {
+			name
+		}
+	}
+`
+	res, err := Parse(Request{Str: query})
+	require.NoError(t, err)
+	filter := res.Query[0].Filter
+	want := filter.debugString()
+
+	clone := filter.Clone()
+	require.Equal(t, want, clone.debugString())
+
+	// Changing the clone must leave the original filter as it was.
+	clone.Op = "or"
+	clone.Child[0].Func.Args[0].Value = "bob"
+	clone.Child[1].Child = append(clone.Child[1].Child, &FilterTree{
+		Func: &Function{Name: "has", Attr: "age"},
+	})
+	require.Equal(t, want, filter.debugString())
+	require.NotEqual(t, want, clone.debugString())
+
+	require.Nil(t, (*FilterTree)(nil).Clone())
+}
@@ -853,6 +853,51 @@ func RewriteUpsertQueryFromMutation(
 	return dgQuery
 }
 
+// RewriteXIDQueryFromMutation builds a query like the following for an add mutation m, to find
+// the nodes that already exist with the @id values given in its input:
+//
+//	addPost(func: eq(Post.slug, "a", "b")) @filter(type(Post)) {
+//		uid
+//		Post.slug
+//	}
+//
+// It returns nil if the mutated type has no @id field, as there is nothing to look up. The
+// generated add mutations can't ask for an upsert, so that isn't an error.
+func RewriteXIDQueryFromMutation(m schema.Mutation) (*gql.GraphQuery, error) {
+	if m.MutationType() != schema.AddMutation {
+		return nil, errors.Errorf(
+			"(internal error) call to build @id query for %s mutation type", m.MutationType())
+	}
+
+	typ := m.MutatedType()
+	xid := typ.XIDField()
+	input, _ := m.ArgValue(schema.InputArgName).([]interface{})
+	if xid == nil || len(input) == 0 {
+		return nil, nil
+	}
+
+	xidStrings := make([]string, 0, len(input))
+	for _, obj := range input {
+		objMap, _ := obj.(map[string]interface{})
+		xidVal := objMap[xid.Name()]
+		if xidVal == nil {
+			return nil, errors.Errorf("field %s cannot be empty", xid.Name())
+		}
+		xidString, err := xidAsString(xid, xidVal)
+		if err != nil {
+			return nil, err
+		}
+		xidStrings = append(xidStrings, xidString)
+	}
+
+	qry := checkXIDExistsQuery(m.Name(), xidStrings[0], xid.Name(), typ)
+	for _, xidString := range xidStrings[1:] {
+		qry.Func.Args = append(qry.Func.Args, gql.Arg{Value: maybeQuoteArg("eq", xidString)})
+	}
+	qry.Children = append(qry.Children, &gql.GraphQuery{Attr: typ.DgraphPredicate(xid.Name())})
+	return qry, nil
+}
+
 // removeNodeReference removes any reference we know about (via @hasInverse) into a node.
 func removeNodeReference(m schema.Mutation, authRw *authRewriter,
 	qry *gql.GraphQuery) []interface{} {
@@ -1101,6 +1146,36 @@ func queryFromFragments(frags []*mutationFragment) *gql.GraphQuery {
 	return qry
 }
 
+// xidAsString returns xidVal, the value given for the @id field xid, as a string that can be
+// used in a DQL query.
+func xidAsString(xid schema.FieldDefinition, xidVal interface{}) (string, error) {
+	switch xid.Type().Name() {
+	case "Int":
+		val, ok := xidVal.(int64)
+		if !ok {
+			return "", errors.New(fmt.Sprintf("encountered an XID %s with %s that isn't "+
+				"a Int but data type in schema is Int", xid.Name(), xid.Type().Name()))
+		}
+		return strconv.FormatInt(val, 10), nil
+	case "Float":
+		val, ok := xidVal.(float64)
+		if !ok {
+			return "", errors.New(fmt.Sprintf("encountered an XID %s with %s that isn't "+
+				"a Float but data type in schema is Float", xid.Name(), xid.Type().Name()))
+		}
+		return strconv.FormatFloat(val, 'f', -1, 64), nil
+	case "Int64":
+		fallthrough
+	default:
+		val, ok := xidVal.(string)
+		if !ok {
+			return "", errors.New(fmt.Sprintf("encountered an XID %s with %s that isn't "+
+				"a String or Int64", xid.Name(), xid.Type().Name()))
+		}
+		return val, nil
+	}
+}
+
 func checkXIDExistsQuery(xidVariable, xidString, xidPredicate string, typ schema.Type) *gql.GraphQuery {
 	qry := &gql.GraphQuery{
 		Attr: xidVariable,
@@ -1538,35 +1613,12 @@ func existenceQueries(
 	}
 
 	xid := typ.XIDField()
-	var xidString string
 	if xid != nil {
 		if xidVal, ok := obj[xid.Name()]; ok && xidVal != nil {
-			switch xid.Type().Name() {
-			case "Int":
-				val, ok := xidVal.(int64)
-				if !ok {
-					retErrors = append(retErrors, errors.New(fmt.Sprintf("encountered an XID %s with %s that isn't "+
-						"a Int but data type in schema is Int", xid.Name(), xid.Type().Name())))
-					return nil, retErrors
-				}
-				xidString = strconv.FormatInt(val, 10)
-			case "Float":
-				val, ok := xidVal.(float64)
-				if !ok {
-					retErrors = append(retErrors, errors.New(fmt.Sprintf("encountered an XID %s with %s that isn't "+
-						"a Float but data type in schema is Float", xid.Name(), xid.Type().Name())))
-					return nil, retErrors
-				}
-				xidString = strconv.FormatFloat(val, 'f', -1, 64)
-			case "Int64":
-				fallthrough
-			default:
-				xidString, ok = xidVal.(string)
-				if !ok {
-					retErrors = append(retErrors, errors.New(fmt.Sprintf("encountered an XID %s with %s that isn't "+
-						"a String or Int64", xid.Name(), xid.Type().Name())))
-					return nil, retErrors
-				}
+			xidString, err := xidAsString(xid, xidVal)
+			if err != nil {
+				retErrors = append(retErrors, err)
+				return nil, retErrors
 			}
 			variable := varGen.Next(typ, xid.Name(), xidString, false)
 
@@ -27,6 +27,7 @@ import (
 	dgoapi "github.com/dgraph-io/dgo/v200/protos/api"
 	"github.com/dgraph-io/dgraph/testutil"
 
+	"github.com/dgraph-io/dgraph/gql"
 	"github.com/dgraph-io/dgraph/graphql/dgraph"
 	"github.com/dgraph-io/dgraph/graphql/schema"
 	"github.com/dgraph-io/dgraph/graphql/test"
@@ -433,3 +434,45 @@ func TestCustomHTTPMutation(t *testing.T) {
 		})
 	}
 }
+
+func TestRewriteXIDQueryFromMutation(t *testing.T) {
+	gqlSchema := test.LoadSchemaFromString(t, `
+	type Post {
+		id: ID!
+		slug: String! @id
+		title: String
+	}
+
+	type Author {
+		id: ID!
+		name: String
+	}`)
+
+	op, err := gqlSchema.Operation(&schema.Request{
+		Query: `mutation {
+			addPost(input: [{slug: "first", title: "A"}, {slug: "second"}]) {
+				numUids
+			}
+			addAuthor(input: [{name: "alice"}]) {
+				numUids
+			}
+		}`,
+	})
+	require.NoError(t, err)
+	mutations := op.Mutations()
+	require.Len(t, mutations, 2)
+
+	qry, err := RewriteXIDQueryFromMutation(mutations[0])
+	require.NoError(t, err)
+	require.Equal(t, `query {
+  addPost(func: eq(Post.slug, "first", "second")) @filter(type(Post)) {
+    uid
+    Post.slug
+  }
+}`, dgraph.AsString([]*gql.GraphQuery{qry}))
+
+	// Author has no @id field, so there is nothing to look up.
+	qry, err = RewriteXIDQueryFromMutation(mutations[1])
+	require.NoError(t, err)
+	require.Nil(t, qry)
+}
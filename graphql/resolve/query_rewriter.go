@@ -1605,6 +1605,27 @@ func addFilter(q *gql.GraphQuery, typ schema.Type, filter map[string]interface{}
 	return true
 }
 
+// FilterTree translates the filter argument of f into the gql.FilterTree that the rewriter
+// uses for the @filter of f in the DQL query. It returns nil if f has no filter. For a union
+// field, it also returns nil if the filter excludes all the member types.
+func FilterTree(f schema.Field) (*gql.FilterTree, error) {
+	arg := f.ArgValue("filter")
+	if arg == nil {
+		return nil, nil
+	}
+	filter, ok := arg.(map[string]interface{})
+	if !ok {
+		return nil, f.Errorf("filter argument of %s should be an object, but got %T",
+			f.Name(), arg)
+	}
+
+	if typ := f.Type(); typ.IsUnion() {
+		ft, _ := buildUnionFilter(typ, filter)
+		return ft, nil
+	}
+	return buildFilter(f.Type(), filter), nil
+}
+
 // buildFilter builds a Dgraph gql.FilterTree from a GraphQL 'filter' arg.
 //
 // All the 'filter' args built by the GraphQL layer look like
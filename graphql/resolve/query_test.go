@@ -24,6 +24,7 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/dgraph-io/dgraph/gql"
 	"github.com/dgraph-io/dgraph/graphql/dgraph"
 	"github.com/dgraph-io/dgraph/graphql/schema"
 	"github.com/dgraph-io/dgraph/graphql/test"
@@ -162,3 +163,45 @@ func TestCustomHTTPQuery(t *testing.T) {
 		})
 	}
 }
+
+func TestFilterTree(t *testing.T) {
+	gqlSchema := test.LoadSchemaFromFile(t, "schema.graphql")
+
+	op, err := gqlSchema.Operation(&schema.Request{Query: `query {
+		queryPost(filter: {
+			title: { anyofterms: "GraphQL" },
+			or: [
+				{ numLikes: { between: { min: 10, max: 20 } } },
+				{ not: { tags: { in: ["a", "b"] } } }
+			]
+		}) {
+			title
+			comments { text }
+		}
+	}`})
+	require.NoError(t, err)
+	q := test.GetQuery(t, op)
+
+	ft, err := FilterTree(q)
+	require.NoError(t, err)
+	require.Equal(t, &gql.FilterTree{
+		Op: "or",
+		Child: []*gql.FilterTree{
+			{Func: &gql.Function{Name: "anyofterms",
+				Args: []gql.Arg{{Value: "Post.title"}, {Value: `"GraphQL"`}}}},
+			{Op: "or", Child: []*gql.FilterTree{
+				{Func: &gql.Function{Name: "between",
+					Args: []gql.Arg{{Value: "Post.numLikes"}, {Value: "10"}, {Value: "20"}}}},
+				{Op: "not", Child: []*gql.FilterTree{
+					{Func: &gql.Function{Name: "eq",
+						Args: []gql.Arg{{Value: "Post.tags"}, {Value: `"a"`}, {Value: `"b"`}}}},
+				}},
+			}},
+		},
+	}, ft)
+
+	// A field without a filter has no filter tree.
+	ft, err = FilterTree(q.SelectionSet()[1])
+	require.NoError(t, err)
+	require.Nil(t, ft)
+}
@@ -483,10 +483,8 @@ func (r *RequestResolver) ValidateSubscription(req *schema.Request) error {
 	}
 
 	for _, q := range op.Queries() {
-		for _, field := range q.SelectionSet() {
-			if err := validateCustomFieldsRecursively(field); err != nil {
-				return err
-			}
+		if _, err := q.IsLiveQueryable(); err != nil {
+			return err
 		}
 	}
 	return nil
@@ -496,22 +494,6 @@ func (r *RequestResolver) Schema() schema.Schema {
 	return r.schema
 }
 
-// validateCustomFieldsRecursively will return err if the given field is custom or any of its
-// children is type of a custom field.
-func validateCustomFieldsRecursively(field schema.Field) error {
-	if field.IsCustomHTTP() {
-		return x.GqlErrorf("Custom field `%s` is not supported in graphql subscription",
-			field.Name()).WithLocations(field.Location())
-	}
-	for _, f := range field.SelectionSet() {
-		err := validateCustomFieldsRecursively(f)
-		if err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
 func addResult(resp *schema.Response, res *Resolved) {
 	// Errors should report the "path" into the result where the error was found.
 	//
@@ -21,7 +21,9 @@ import (
 	"fmt"
 	"reflect"
 	"regexp"
+	"sort"
 	"strings"
+	"unicode"
 
 	"github.com/spf13/cast"
 
@@ -80,6 +82,14 @@ func (rq *RBACQuery) checkIfMatchInArray(array []interface{}) RuleResult {
 }
 
 func (rq *RBACQuery) checkIfMatch(value interface{}) RuleResult {
+	if rq.Operator == "between" {
+		// the operand has already been validated to be an array of two Strings or Numbers
+		bounds := rq.Operand.([]interface{})
+		return evaluateBetween(bounds[0], bounds[1], value)
+	}
+	if rq.Operator == "anyofterms" || rq.Operator == "allofterms" {
+		return evaluateTerms(rq.Operand.(string), value, rq.Operator == "allofterms")
+	}
 	rules, ok := rq.Operand.([]interface{})
 	if ok {
 		// this means rule operand is array slice
@@ -109,6 +119,60 @@ func evaluate(operand interface{}, value interface{}, regex *regexp.Regexp) Rule
 	return Negative
 }
 
+// evaluateBetween checks whether value lies in the inclusive range [low, high]. String bounds
+// are compared lexicographically, so they work for dates in the ISO 8601 format.
+func evaluateBetween(low, high, value interface{}) RuleResult {
+	if lowStr, ok := low.(string); ok {
+		val, ok := value.(string)
+		if ok && lowStr <= val && val <= high.(string) {
+			return Positive
+		}
+		return Negative
+	}
+
+	if value == nil {
+		return Negative
+	}
+	val, err := cast.ToFloat64E(value)
+	if err == nil && low.(float64) <= val && val <= high.(float64) {
+		return Positive
+	}
+	return Negative
+}
+
+// evaluateTerms checks whether value, which should be a string, contains any (or all, if
+// matchAll is true) of the terms in operand. Like Dgraph's term index, terms are compared
+// case insensitively and are separated by anything other than letters and digits.
+func evaluateTerms(operand string, value interface{}, matchAll bool) RuleResult {
+	sval, ok := value.(string)
+	if !ok {
+		return Negative
+	}
+
+	valueTerms := make(map[string]bool)
+	for _, term := range toTerms(sval) {
+		valueTerms[term] = true
+	}
+	for _, term := range toTerms(operand) {
+		if valueTerms[term] && !matchAll {
+			return Positive
+		}
+		if !valueTerms[term] && matchAll {
+			return Negative
+		}
+	}
+	if matchAll {
+		return Positive
+	}
+	return Negative
+}
+
+func toTerms(str string) []string {
+	return strings.FieldsFunc(strings.ToLower(str), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
 // EvaluateRBACRule evaluates the auth token based on the auth query
 // There are two cases here:
 // 1. Auth token has an array of values for the variable.
@@ -117,7 +181,18 @@ func evaluate(operand interface{}, value interface{}, regex *regexp.Regexp) Rule
 // In case array one match would made the rule positive.
 // For example, Rule {$USER: { eq:"uid"}} and token $USER:["u", "id", "uid"] result in match.
 // Rule {$USER: { in: ["uid", "xid"]}} and token $USER:["u", "id", "uid"]  result in match
+// Rule {$LEVEL: { between: [1, 5]}} and token $LEVEL:3 result in match.
+// Rule {$TITLE: { anyofterms: "engineer manager"}} and token $TITLE:"Engineering Manager" result
+// in match, while allofterms would need both the terms to be present.
+// Rule {$USER: { has: true }} results in match if the token has any non-null value for $USER.
 func (rq *RBACQuery) EvaluateRBACRule(av map[string]interface{}) RuleResult {
+	// if has, the rule only checks whether the variable is present in the token or not
+	if rq.Operator == "has" {
+		if val, ok := av[rq.Variable]; (ok && val != nil) == rq.Operand.(bool) {
+			return Positive
+		}
+		return Negative
+	}
 	tokenValues, tokenCastErr := cast.ToSliceE(av[rq.Variable])
 	// if eq, auth rule value will be matched completely
 	// if regexp, auth rule value should always be string and so as token values
@@ -197,6 +272,268 @@ func (node *RuleNode) EvaluateStatic(av map[string]interface{}) RuleResult {
 	return Uncertain
 }
 
+// RBACEvaluator returns a function which evaluates node against the claims of a JWT, for
+// rules that are made only of RBAC rules combined with and/or/not. Such rules can be checked
+// without querying Dgraph. It returns nil if node has any GraphQL or DQL rule in it.
+func (node *RuleNode) RBACEvaluator() func(claims map[string]interface{}) bool {
+	if !node.isRBAC() {
+		return nil
+	}
+	return node.evaluateRBAC
+}
+
+// isRBAC tells whether node, and all its children, are RBAC rules or and/or/not of those.
+func (node *RuleNode) isRBAC() bool {
+	if node == nil {
+		return false
+	}
+
+	switch {
+	case len(node.Or) > 0:
+		for _, rule := range node.Or {
+			if !rule.isRBAC() {
+				return false
+			}
+		}
+		return true
+	case len(node.And) > 0:
+		for _, rule := range node.And {
+			if !rule.isRBAC() {
+				return false
+			}
+		}
+		return true
+	case node.Not != nil:
+		return node.Not.isRBAC()
+	default:
+		return node.RBACRule != nil
+	}
+}
+
+func (node *RuleNode) evaluateRBAC(claims map[string]interface{}) bool {
+	switch {
+	case len(node.Or) > 0:
+		for _, rule := range node.Or {
+			if rule.evaluateRBAC(claims) {
+				return true
+			}
+		}
+		return false
+	case len(node.And) > 0:
+		for _, rule := range node.And {
+			if !rule.evaluateRBAC(claims) {
+				return false
+			}
+		}
+		return true
+	case node.Not != nil:
+		return !node.Not.evaluateRBAC(claims)
+	default:
+		return node.RBACRule.EvaluateRBACRule(claims) == Positive
+	}
+}
+
+// String renders an RBAC rule like: $ROLE eq "ADMIN"
+func (rq *RBACQuery) String() string {
+	if operand, ok := rq.Operand.(string); ok {
+		return fmt.Sprintf("$%s %s %q", rq.Variable, rq.Operator, operand)
+	}
+	return fmt.Sprintf("$%s %s %v", rq.Variable, rq.Operator, rq.Operand)
+}
+
+// String renders the rule tree rooted at node with one line per node, indenting the children
+// of and/or/not nodes. For example:
+//
+//	or
+//	  rule: $ROLE eq "ADMIN"
+//	  rule: queryPost($USER)
+func (node *RuleNode) String() string {
+	var b strings.Builder
+	node.writeTo(&b, 0)
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func (node *RuleNode) writeTo(b *strings.Builder, depth int) {
+	if node == nil {
+		return
+	}
+	indent := strings.Repeat("  ", depth)
+
+	switch {
+	case len(node.Or) > 0:
+		b.WriteString(indent + "or\n")
+		for _, rule := range node.Or {
+			rule.writeTo(b, depth+1)
+		}
+	case len(node.And) > 0:
+		b.WriteString(indent + "and\n")
+		for _, rule := range node.And {
+			rule.writeTo(b, depth+1)
+		}
+	case node.Not != nil:
+		b.WriteString(indent + "not\n")
+		node.Not.writeTo(b, depth+1)
+	case node.RBACRule != nil:
+		b.WriteString(indent + "rule: " + node.RBACRule.String() + "\n")
+	case node.Rule != nil:
+		vars := make([]string, 0, len(node.Variables))
+		for _, v := range node.Variables {
+			vars = append(vars, "$"+v.Variable)
+		}
+		b.WriteString(fmt.Sprintf("%srule: %s(%s)\n", indent, node.Rule.Name(),
+			strings.Join(vars, ", ")))
+	case node.DQLRule != nil:
+		b.WriteString(indent + "dql: " + node.DQLRule.Attr + "\n")
+	}
+}
+
+// collectJWTClaims adds the names of all the JWT claims referenced by node, or any of its
+// children, to claims.
+func (node *RuleNode) collectJWTClaims(claims map[string]bool) {
+	if node == nil {
+		return
+	}
+	for _, rule := range node.Or {
+		rule.collectJWTClaims(claims)
+	}
+	for _, rule := range node.And {
+		rule.collectJWTClaims(claims)
+	}
+	node.Not.collectJWTClaims(claims)
+
+	if node.RBACRule != nil {
+		claims[node.RBACRule.Variable] = true
+	}
+	for _, v := range node.Variables {
+		claims[v.Variable] = true
+	}
+}
+
+// collectJWTClaims adds the names of all the JWT claims referenced by any of the rules in c
+// to claims.
+func (c *AuthContainer) collectJWTClaims(claims map[string]bool) {
+	if c == nil {
+		return
+	}
+	c.Password.collectJWTClaims(claims)
+	c.Query.collectJWTClaims(claims)
+	c.Add.collectJWTClaims(claims)
+	c.Update.collectJWTClaims(claims)
+	c.Delete.collectJWTClaims(claims)
+}
+
+// ReadPredicates returns the sorted Dgraph predicates read while evaluating the GraphQL rules in
+// node, or any of its children. That includes the predicates traversed by a rule as well as the
+// ones used in the filters of a rule.
+func (node *RuleNode) ReadPredicates() []string {
+	preds := make(map[string]bool)
+	node.collectReadPredicates(preds)
+
+	result := make([]string, 0, len(preds))
+	for pred := range preds {
+		result = append(result, pred)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func (node *RuleNode) collectReadPredicates(preds map[string]bool) {
+	if node == nil {
+		return
+	}
+	for _, rule := range node.Or {
+		rule.collectReadPredicates(preds)
+	}
+	for _, rule := range node.And {
+		rule.collectReadPredicates(preds)
+	}
+	node.Not.collectReadPredicates(preds)
+
+	if rule, ok := node.Rule.(*query); ok {
+		// The rule is a template shared by all the requests, so walk a copy of it, as the
+		// field caches its arguments and type.
+		collectFieldPredicates(&field{field: rule.field, op: rule.op, sel: rule.sel}, preds)
+	}
+}
+
+// collectFieldPredicates adds the Dgraph predicates used in the filter of f, and the ones
+// for the fields selected under f, to preds.
+func collectFieldPredicates(f Field, preds map[string]bool) {
+	if filter, ok := f.ArgValue("filter").(map[string]interface{}); ok {
+		collectFilterPredicates(f.Type(), filter, preds)
+	}
+	for _, child := range f.SelectionSet() {
+		if pred := child.DgraphPredicate(); pred != "" {
+			preds[pred] = true
+		}
+		collectFieldPredicates(child, preds)
+	}
+}
+
+func collectFilterPredicates(typ Type, filter map[string]interface{}, preds map[string]bool) {
+	for key, val := range filter {
+		switch key {
+		case "and", "or", "not":
+			switch val := val.(type) {
+			case map[string]interface{}:
+				collectFilterPredicates(typ, val, preds)
+			case []interface{}:
+				for _, v := range val {
+					if v, ok := v.(map[string]interface{}); ok {
+						collectFilterPredicates(typ, v, preds)
+					}
+				}
+			}
+		case "has":
+			fields, ok := val.([]interface{})
+			if !ok {
+				fields = []interface{}{val}
+			}
+			for _, fld := range fields {
+				if name, ok := fld.(string); ok && typ.DgraphPredicate(name) != "" {
+					preds[typ.DgraphPredicate(name)] = true
+				}
+			}
+		default:
+			if pred := typ.DgraphPredicate(key); pred != "" {
+				preds[pred] = true
+			}
+		}
+	}
+}
+
+// setSchema sets the schema that the GraphQL rules in node, or any of its children, are
+// evaluated against. Rules are parsed before the schema is built, so this is done once the
+// schema is ready.
+func (node *RuleNode) setSchema(s *schema) {
+	if node == nil {
+		return
+	}
+	for _, rule := range node.Or {
+		rule.setSchema(s)
+	}
+	for _, rule := range node.And {
+		rule.setSchema(s)
+	}
+	node.Not.setSchema(s)
+
+	if rule, ok := node.Rule.(*query); ok {
+		rule.op.inSchema = s
+	}
+}
+
+// setSchema sets the schema that the GraphQL rules in c are evaluated against.
+func (c *AuthContainer) setSchema(s *schema) {
+	if c == nil {
+		return
+	}
+	c.Password.setSchema(s)
+	c.Query.setSchema(s)
+	c.Add.setSchema(s)
+	c.Update.setSchema(s)
+	c.Delete.setSchema(s)
+}
+
 type TypeAuth struct {
 	Rules  *AuthContainer
 	Fields map[string]*AuthContainer
@@ -320,6 +657,25 @@ func mergeAuthRules(
 	return objectAuthRules
 }
 
+// Merge returns a new AuthContainer whose rules require both the rules in c and those in other
+// to be satisfied. If either of c or other is nil, the other one is returned.
+func (c *AuthContainer) Merge(other *AuthContainer) *AuthContainer {
+	if c == nil {
+		return other
+	}
+	if other == nil {
+		return c
+	}
+
+	return &AuthContainer{
+		Password: mergeAuthNodeWithAnd(c.Password, other.Password),
+		Query:    mergeAuthNodeWithAnd(c.Query, other.Query),
+		Add:      mergeAuthNodeWithAnd(c.Add, other.Add),
+		Update:   mergeAuthNodeWithAnd(c.Update, other.Update),
+		Delete:   mergeAuthNodeWithAnd(c.Delete, other.Delete),
+	}
+}
+
 func parseAuthDirective(
 	s *ast.Schema,
 	typ *ast.Definition,
@@ -510,6 +866,36 @@ func validateRBACOperators(typ *ast.Definition, query *RBACQuery) (bool, string)
 			return false, fmt.Sprintf("Type %s: @auth: `%s` operator has invalid value `%v`."+
 				" Value should be of type String.", typ.Name, query.Operator, query.Operand)
 		}
+	case "has":
+		// auth rule value should be a boolean telling whether the variable should be present
+		_, ok := query.Operand.(bool)
+		if !ok {
+			return false, fmt.Sprintf("Type %s: @auth: `%s` operator has invalid value `%v`."+
+				" Value should be of type Boolean.", typ.Name, query.Operator, query.Operand)
+		}
+	case "between":
+		// auth rule value should be an array of two Strings or two Numbers
+		bounds, ok := query.Operand.([]interface{})
+		if ok && len(bounds) == 2 {
+			_, lowStr := bounds[0].(string)
+			_, highStr := bounds[1].(string)
+			_, lowNum := bounds[0].(float64)
+			_, highNum := bounds[1].(float64)
+			ok = (lowStr && highStr) || (lowNum && highNum)
+		}
+		if !ok {
+			return false, fmt.Sprintf("Type %s: @auth: `%s` operator has invalid value `%v`."+
+				" Value should be an array of two Strings or two Numbers.", typ.Name,
+				query.Operator, query.Operand)
+		}
+	case "anyofterms", "allofterms":
+		// auth rule value should be a string having at least one term
+		str, ok := query.Operand.(string)
+		if !ok || len(toTerms(str)) == 0 {
+			return false, fmt.Sprintf("Type %s: @auth: `%s` operator has invalid value `%v`."+
+				" Value should be a String having at least one term.", typ.Name,
+				query.Operator, query.Operand)
+		}
 	case "in":
 		// auth rule value should be of array type
 		_, ok := query.Operand.([]interface{})
@@ -579,7 +965,7 @@ func gqlValidateRule(s *ast.Schema, typ *ast.Definition, rule string, node *Rule
 		op: &operation{op: op,
 			query: rule,
 			doc:   doc,
-			// need to fill in vars and schema at query time
+			// the schema is filled in by AsSchema once it is built, and vars at query time
 		},
 		sel: op.SelectionSet[0]}
 	node.Variables = op.VariableDefinitions
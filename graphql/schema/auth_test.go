@@ -0,0 +1,434 @@
+/*
+ * Copyright 2020 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequiredJWTClaims(t *testing.T) {
+	schemaStr := `
+	type Post @auth(
+		query: { rule: "{$ROLE: { eq: \"ADMIN\" } }" },
+		add: { rule: """
+			query($USER: String!) {
+				queryPost(filter: { author: { eq: $USER } }) {
+					id
+				}
+			}"""
+		}
+	) {
+		id: ID!
+		author: String! @search(by: [hash])
+	}
+
+	type Comment @auth(
+		query: { or: [
+			{ rule: "{$USER: { eq: \"alice\" } }" },
+			{ rule: "{$ROLE: { eq: \"ADMIN\" } }" }
+		]}
+	) {
+		id: ID!
+		text: String
+	}
+
+	type Author {
+		id: ID!
+		name: String
+	}`
+
+	schHandler, errs := NewHandler(schemaStr, false)
+	require.NoError(t, errs)
+	sch, err := FromString(schHandler.GQLSchema())
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"ROLE", "USER"}, sch.RequiredJWTClaims())
+}
+
+func TestRBACHasRule(t *testing.T) {
+	schemaStr := `
+	type Post @auth(
+		query: { rule: "{$USER: { has: true } }" },
+		add: { rule: "{$USER: { has: false } }" }
+	) {
+		id: ID!
+		text: String
+	}`
+
+	schHandler, errs := NewHandler(schemaStr, false)
+	require.NoError(t, errs)
+	sch, err := FromString(schHandler.GQLSchema())
+	require.NoError(t, err)
+
+	rules := sch.(*schema).authRules["Post"].Rules
+	require.NotNil(t, rules.Query.RBACRule)
+	require.Equal(t, "has", rules.Query.RBACRule.Operator)
+
+	tcases := []struct {
+		name   string
+		claims map[string]interface{}
+		query  RuleResult
+		add    RuleResult
+	}{
+		{"claim present", map[string]interface{}{"USER": "alice"}, Positive, Negative},
+		{"claim present as list", map[string]interface{}{"USER": []interface{}{"a"}},
+			Positive, Negative},
+		{"claim null", map[string]interface{}{"USER": nil}, Negative, Positive},
+		{"claim absent", map[string]interface{}{"ROLE": "ADMIN"}, Negative, Positive},
+	}
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			require.Equal(t, tcase.query, rules.Query.EvaluateStatic(tcase.claims))
+			require.Equal(t, tcase.add, rules.Add.EvaluateStatic(tcase.claims))
+		})
+	}
+}
+
+func TestRuleNodeString(t *testing.T) {
+	schemaStr := `
+	type Post @auth(
+		query: { or: [
+			{ rule: "{$ROLE: { eq: \"ADMIN\" } }" },
+			{ and: [
+				{ rule: """
+					query($USER: String!) {
+						queryPost(filter: { author: { eq: $USER } }) {
+							id
+						}
+					}"""
+				},
+				{ not: { rule: "{$DISABLED: { eq: true } }" } }
+			]}
+		]}
+	) {
+		id: ID!
+		author: String! @search(by: [hash])
+	}`
+
+	schHandler, errs := NewHandler(schemaStr, false)
+	require.NoError(t, errs)
+	sch, err := FromString(schHandler.GQLSchema())
+	require.NoError(t, err)
+
+	require.Equal(t, `or
+  rule: $ROLE eq "ADMIN"
+  and
+    rule: queryPost($USER)
+    not
+      rule: $DISABLED eq true`, sch.(*schema).authRules["Post"].Rules.Query.String())
+}
+
+func TestRBACBetweenRule(t *testing.T) {
+	schemaStr := `
+	type Post @auth(
+		query: { rule: "{$LEVEL: { between: [1, 5] } }" },
+		add: { rule: "{$JOINED: { between: [\"2020-01-01\", \"2020-12-31\"] } }" }
+	) {
+		id: ID!
+		text: String
+	}`
+
+	schHandler, errs := NewHandler(schemaStr, false)
+	require.NoError(t, errs)
+	sch, err := FromString(schHandler.GQLSchema())
+	require.NoError(t, err)
+
+	rules := sch.(*schema).authRules["Post"].Rules
+	tcases := []struct {
+		name   string
+		claims map[string]interface{}
+		query  RuleResult
+		add    RuleResult
+	}{
+		{"inside the range",
+			map[string]interface{}{"LEVEL": 3.0, "JOINED": "2020-06-15"}, Positive, Positive},
+		{"on the bounds",
+			map[string]interface{}{"LEVEL": 5.0, "JOINED": "2020-01-01"}, Positive, Positive},
+		{"outside the range",
+			map[string]interface{}{"LEVEL": 6.0, "JOINED": "2021-01-01"}, Negative, Negative},
+		{"one of the list values inside the range",
+			map[string]interface{}{"LEVEL": []interface{}{0.0, 2.0}}, Positive, Negative},
+		{"claim absent", map[string]interface{}{}, Negative, Negative},
+	}
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			require.Equal(t, tcase.query, rules.Query.EvaluateStatic(tcase.claims))
+			require.Equal(t, tcase.add, rules.Add.EvaluateStatic(tcase.claims))
+		})
+	}
+}
+
+func TestAuthContainerMerge(t *testing.T) {
+	typeQuery := &RuleNode{RBACRule: &RBACQuery{Variable: "ROLE", Operator: "eq",
+		Operand: "ADMIN"}}
+	typeAdd := &RuleNode{RBACRule: &RBACQuery{Variable: "ROLE", Operator: "eq",
+		Operand: "EDITOR"}}
+	fieldQuery := &RuleNode{RBACRule: &RBACQuery{Variable: "USER", Operator: "has",
+		Operand: true}}
+
+	typeAuth := &AuthContainer{Query: typeQuery, Add: typeAdd}
+	fieldAuth := &AuthContainer{Query: fieldQuery}
+
+	merged := typeAuth.Merge(fieldAuth)
+	require.Equal(t, &RuleNode{And: []*RuleNode{typeQuery, fieldQuery}}, merged.Query)
+	require.Same(t, typeAdd, merged.Add)
+	require.Nil(t, merged.Update)
+	require.Nil(t, merged.Delete)
+	require.Nil(t, merged.Password)
+
+	// the merged rules are a new container, the original ones are left as they were
+	require.Same(t, typeQuery, typeAuth.Query)
+	require.Same(t, fieldQuery, fieldAuth.Query)
+
+	require.Equal(t, Negative, merged.Query.EvaluateStatic(map[string]interface{}{
+		"ROLE": "ADMIN"}))
+	require.Equal(t, Positive, merged.Query.EvaluateStatic(map[string]interface{}{
+		"ROLE": "ADMIN", "USER": "alice"}))
+
+	require.Same(t, typeAuth, typeAuth.Merge(nil))
+	var noAuth *AuthContainer
+	require.Same(t, fieldAuth, noAuth.Merge(fieldAuth))
+}
+
+func TestIsAuthRequired(t *testing.T) {
+	schemaStr := `
+	type Post @auth(
+		query: { rule: "{$ROLE: { eq: \"ADMIN\" } }" }
+	) {
+		id: ID!
+		text: String
+	}
+
+	type Comment @auth(
+		query: { rule: "{$ROLE: { eq: \"ADMIN\" } }" },
+		add: { rule: "{$ROLE: { eq: \"ADMIN\" } }" },
+		update: { rule: "{$ROLE: { eq: \"ADMIN\" } }" },
+		delete: { rule: "{$ROLE: { eq: \"ADMIN\" } }" }
+	) {
+		id: ID!
+		text: String
+	}
+
+	type Author {
+		id: ID!
+		name: String
+	}`
+
+	schHandler, errs := NewHandler(schemaStr, false)
+	require.NoError(t, errs)
+	sch, err := FromString(schHandler.GQLSchema())
+	require.NoError(t, err)
+
+	for _, op := range []MutationType{AddMutation, UpdateMutation, DeleteMutation} {
+		require.False(t, sch.IsAuthRequired("Post", op), "Post %s", op)
+		require.True(t, sch.IsAuthRequired("Comment", op), "Comment %s", op)
+		require.False(t, sch.IsAuthRequired("Author", op), "Author %s", op)
+		require.False(t, sch.IsAuthRequired("Unknown", op), "Unknown %s", op)
+	}
+	require.False(t, sch.IsAuthRequired("Comment", HTTPMutation))
+}
+
+func TestRBACEvaluator(t *testing.T) {
+	schemaStr := `
+	type Post @auth(
+		query: { rule: "{$ROLE: { eq: \"ADMIN\" } }" },
+		add: { or: [
+			{ rule: "{$ROLE: { eq: \"ADMIN\" } }" },
+			{ and: [
+				{ rule: "{$ROLE: { eq: \"EDITOR\" } }" },
+				{ not: { or: [
+					{ rule: "{$DISABLED: { eq: true } }" },
+					{ rule: "{$SUSPENDED: { eq: true } }" }
+				]}}
+			]}
+		]},
+		update: { rule: """
+			query($USER: String!) {
+				queryPost(filter: { author: { eq: $USER } }) {
+					id
+				}
+			}"""
+		}
+	) {
+		id: ID!
+		author: String! @search(by: [hash])
+	}`
+
+	schHandler, errs := NewHandler(schemaStr, false)
+	require.NoError(t, errs)
+	sch, err := FromString(schHandler.GQLSchema())
+	require.NoError(t, err)
+	rules := sch.(*schema).authRules["Post"].Rules
+
+	query := rules.Query.RBACEvaluator()
+	require.NotNil(t, query)
+	require.True(t, query(map[string]interface{}{"ROLE": "ADMIN"}))
+	require.False(t, query(map[string]interface{}{"ROLE": "USER"}))
+	require.False(t, query(map[string]interface{}{}))
+
+	add := rules.Add.RBACEvaluator()
+	require.NotNil(t, add)
+	require.True(t, add(map[string]interface{}{"ROLE": "ADMIN", "DISABLED": true}))
+	require.True(t, add(map[string]interface{}{"ROLE": "EDITOR"}))
+	require.False(t, add(map[string]interface{}{"ROLE": "EDITOR", "SUSPENDED": true}))
+
+	require.Nil(t, rules.Update.RBACEvaluator())
+	require.Nil(t, rules.Delete.RBACEvaluator())
+}
+
+func TestRBACTermsRules(t *testing.T) {
+	schemaStr := `
+	type Post @auth(
+		query: { rule: "{$TITLE: { anyofterms: \"engineer manager\" } }" },
+		add: { rule: "{$TITLE: { allofterms: \"engineer manager\" } }" }
+	) {
+		id: ID!
+		text: String
+	}`
+
+	schHandler, errs := NewHandler(schemaStr, false)
+	require.NoError(t, errs)
+	sch, err := FromString(schHandler.GQLSchema())
+	require.NoError(t, err)
+
+	rules := sch.(*schema).authRules["Post"].Rules
+	tcases := []struct {
+		name   string
+		claims map[string]interface{}
+		query  RuleResult
+		add    RuleResult
+	}{
+		{"all the terms", map[string]interface{}{"TITLE": "Engineer, Manager"}, Positive, Positive},
+		{"one of the terms", map[string]interface{}{"TITLE": "Senior Engineer"}, Positive, Negative},
+		{"none of the terms", map[string]interface{}{"TITLE": "Engineering Lead"}, Negative,
+			Negative},
+		{"terms spread over a list", map[string]interface{}{
+			"TITLE": []interface{}{"engineer", "manager"}}, Positive, Negative},
+		{"claim absent", map[string]interface{}{}, Negative, Negative},
+	}
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			require.Equal(t, tcase.query, rules.Query.EvaluateStatic(tcase.claims))
+			require.Equal(t, tcase.add, rules.Add.EvaluateStatic(tcase.claims))
+		})
+	}
+}
+
+func TestAuthFieldRulesAll(t *testing.T) {
+	sch := schemaFromString(t, `
+	type Post @dgraph(type: "Article") {
+		id: ID!
+		text: String
+		secret: String
+		score: Int
+	}`)
+
+	// @auth isn't allowed on field definitions yet, so the field rules are set directly.
+	textRules := &AuthContainer{Query: &RuleNode{RBACRule: &RBACQuery{
+		Variable: "ROLE", Operator: "eq", Operand: "ADMIN"}}}
+	secretRules := &AuthContainer{Query: &RuleNode{RBACRule: &RBACQuery{
+		Variable: "ROLE", Operator: "eq", Operand: "OWNER"}}}
+	sch.(*schema).authRules["Article"].Fields["text"] = textRules
+	sch.(*schema).authRules["Article"].Fields["secret"] = secretRules
+
+	fields := sch.AuthFieldRulesAll("Post")
+	require.Equal(t, map[string]*AuthContainer{"text": textRules, "secret": secretRules}, fields)
+
+	// The returned map is a copy.
+	delete(fields, "text")
+	require.Len(t, sch.AuthFieldRulesAll("Post"), 2)
+
+	require.Nil(t, sch.AuthFieldRulesAll("Unknown"))
+}
+
+func TestAuthControlledTypes(t *testing.T) {
+	sch := schemaFromString(t, `
+	type Post @auth(
+		query: { rule: "{$ROLE: { eq: \"ADMIN\" } }" }
+	) {
+		id: ID!
+		text: String
+	}
+
+	type Author {
+		id: ID!
+		name: String
+	}
+
+	type Comment {
+		id: ID!
+		text: String
+	}`)
+
+	// @auth isn't allowed on field definitions yet, so the field rules are set directly.
+	sch.(*schema).authRules["Author"].Fields["name"] = &AuthContainer{
+		Query: &RuleNode{RBACRule: &RBACQuery{Variable: "ROLE", Operator: "eq", Operand: "ADMIN"}},
+	}
+
+	require.Equal(t, []string{"Author", "Post"}, sch.AuthControlledTypes())
+}
+
+func TestReadPredicates(t *testing.T) {
+	schemaStr := `
+	type Post @auth(
+		query: { or: [
+			{ rule: """
+				query($USER: String!) {
+					queryPost {
+						author(filter: { username: { eq: $USER } }) {
+							username
+						}
+					}
+				}"""
+			},
+			{ rule: """
+				query {
+					queryPost(filter: { isPublic: true }) {
+						id
+					}
+				}"""
+			}
+		]}
+	) {
+		id: ID!
+		isPublic: Boolean @search
+		author: User
+	}
+
+	type User @dgraph(type: "Person") {
+		username: String! @id
+	}`
+
+	schHandler, errs := NewHandler(schemaStr, false)
+	require.NoError(t, errs)
+	sch, err := FromString(schHandler.GQLSchema())
+	require.NoError(t, err)
+
+	rules := sch.(*schema).authRules["Post"].Rules
+	require.Equal(t, []string{"Person.username", "Post.author"},
+		rules.Query.Or[0].ReadPredicates())
+	require.Equal(t, []string{"Person.username", "Post.author", "Post.isPublic"},
+		rules.Query.ReadPredicates())
+	require.Empty(t, rules.Add.ReadPredicates())
+
+	// the rule templates are shared by concurrent requests, so they must not be written to
+	rule := rules.Query.Or[1].Rule.(*query)
+	require.Nil(t, rule.typ)
+	require.Nil(t, rule.arguments)
+}
@@ -134,7 +134,7 @@ func CompleteObject(
 		f.CompleteAlias(&buf)
 
 		val := res[f.Name()]
-		if f.Name() == Typename {
+		if f.IsTypename() {
 			// From GraphQL spec:
 			// https://graphql.github.io/graphql-spec/June2018/#sec-Type-Name-Introspection
 			// "GraphQL supports type name introspection at any point within a query by the
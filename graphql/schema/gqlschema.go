@@ -39,6 +39,7 @@ const (
 	dgraphPredArg   = "pred"
 
 	idDirective           = "id"
+	idIndexArg            = "index"
 	subscriptionDirective = "withSubscription"
 	secretDirective       = "secret"
 	authDirective         = "auth"
@@ -61,6 +62,15 @@ const (
 	cascadeDirective = "cascade"
 	cascadeArg       = "fields"
 
+	embeddingDirective     = "embedding"
+	embeddingDimensionsArg = "dimensions"
+
+	remoteResponseDirective = "remoteResponse"
+	remoteResponseNameArg   = "name"
+
+	complexityDirective = "complexity"
+	complexityValueArg  = "value"
+
 	cacheControlDirective = "cacheControl"
 	CacheControlHeader    = "Cache-Control"
 
@@ -272,7 +282,7 @@ input GenerateMutationParams {
 directive @hasInverse(field: String!) on FIELD_DEFINITION
 directive @search(by: [DgraphIndex!]) on FIELD_DEFINITION
 directive @dgraph(type: String, pred: String) on OBJECT | INTERFACE | FIELD_DEFINITION
-directive @id on FIELD_DEFINITION
+directive @id(index: String) on FIELD_DEFINITION
 directive @withSubscription on OBJECT | INTERFACE
 directive @secret(field: String!, pred: String) on OBJECT | INTERFACE
 directive @auth(
@@ -290,19 +300,25 @@ directive @generate(
 	query: GenerateQueryParams,
 	mutation: GenerateMutationParams,
 	subscription: Boolean) on OBJECT | INTERFACE
+directive @embedding(dimensions: Int) on FIELD_DEFINITION
+directive @remoteResponse(name: String) on FIELD_DEFINITION
+directive @complexity(value: Int!) on FIELD_DEFINITION
 `
 
 	apolloSupportedDirectiveDefs = `
 directive @hasInverse(field: String!) on FIELD_DEFINITION
 directive @search(by: [DgraphIndex!]) on FIELD_DEFINITION
 directive @dgraph(type: String, pred: String) on OBJECT | INTERFACE | FIELD_DEFINITION
-directive @id on FIELD_DEFINITION
+directive @id(index: String) on FIELD_DEFINITION
 directive @withSubscription on OBJECT | INTERFACE
 directive @secret(field: String!, pred: String) on OBJECT | INTERFACE
 directive @remote on OBJECT | INTERFACE | UNION | INPUT_OBJECT | ENUM
 directive @cascade(fields: [String]) on FIELD
 directive @lambda on FIELD_DEFINITION
 directive @cacheControl(maxAge: Int!) on QUERY
+directive @embedding(dimensions: Int) on FIELD_DEFINITION
+directive @remoteResponse(name: String) on FIELD_DEFINITION
+directive @complexity(value: Int!) on FIELD_DEFINITION
 `
 	filterInputs = `
 input IntFilter {
@@ -557,6 +573,9 @@ var directiveValidators = map[string]directiveValidator{
 	deprecatedDirective:     ValidatorNoOp,
 	lambdaDirective:         lambdaDirectiveValidation,
 	generateDirective:       ValidatorNoOp,
+	embeddingDirective:      embeddingValidation,
+	remoteResponseDirective: remoteResponseValidation,
+	complexityDirective:     complexityValidation,
 	apolloKeyDirective:      ValidatorNoOp,
 	apolloExtendsDirective:  ValidatorNoOp,
 	apolloExternalDirective: apolloExternalValidation,
@@ -575,8 +594,11 @@ var directiveLocationMap = map[string]map[ast.DefinitionKind]bool{
 	customDirective:       nil,
 	remoteDirective: {ast.Object: true, ast.Interface: true, ast.Union: true,
 		ast.InputObject: true, ast.Enum: true},
-	cascadeDirective:  nil,
-	generateDirective: {ast.Object: true, ast.Interface: true},
+	cascadeDirective:        nil,
+	generateDirective:       {ast.Object: true, ast.Interface: true},
+	embeddingDirective:      nil,
+	remoteResponseDirective: nil,
+	complexityDirective:     nil,
 }
 
 // Struct to store parameters of @generate directive
@@ -1602,19 +1624,34 @@ func fieldAny(fields ast.FieldList, pred func(*ast.FieldDefinition) bool) bool {
 	return false
 }
 
-func addHashIfRequired(fld *ast.FieldDefinition, indexes []string) []string {
+// idIndex returns the Dgraph index that the @id field fld gets, which is hash unless exact is
+// given as @id(index: "exact"). Values of the field are looked up with eq for either index, the
+// exact index additionally allows comparing them with lt, le, gt, ge and between in filters.
+// It returns an empty string if fld doesn't have @id.
+func idIndex(fld *ast.FieldDefinition) string {
 	id := fld.Directives.ForName(idDirective)
-	if id != nil {
-		// If @id directive is applied along with @search, we check if the search has hash as an
-		// arg. If it doesn't, then we add it.
-		containsHash := false
+	if id == nil {
+		return ""
+	}
+	if arg := id.Arguments.ForName(idIndexArg); arg != nil && arg.Value.Raw != "" {
+		return arg.Value.Raw
+	}
+	return "hash"
+}
+
+func addIDIndexIfRequired(fld *ast.FieldDefinition, indexes []string) []string {
+	idIdx := idIndex(fld)
+	if idIdx != "" {
+		// If @id directive is applied along with @search, we check if the search has the index
+		// of the @id field as an arg. If it doesn't, then we add it.
+		containsIDIndex := false
 		for _, index := range indexes {
-			if index == "hash" {
-				containsHash = true
+			if index == idIdx {
+				containsIDIndex = true
 			}
 		}
-		if !containsHash {
-			indexes = append(indexes, "hash")
+		if !containsIDIndex {
+			indexes = append(indexes, idIdx)
 		}
 	}
 	return indexes
@@ -1640,10 +1677,10 @@ func getSearchArgs(fld *ast.FieldDefinition) []string {
 			return nil
 		}
 		switch fldType {
-		// If search directive wasn't supplied but id was, then hash is the only index
-		// that we apply for string.
+		// If search directive wasn't supplied but id was, then the index of the @id field is
+		// the only index that we apply for string.
 		case "String":
-			return []string{"hash"}
+			return []string{idIndex(fld)}
 		default:
 			return []string{getDefaultSearchIndex(fldType)}
 		}
@@ -1659,7 +1696,7 @@ func getSearchArgs(fld *ast.FieldDefinition) []string {
 		res[i] = child.Value.Raw
 	}
 
-	res = addHashIfRequired(fld, res)
+	res = addIDIndexIfRequired(fld, res)
 	sort.Strings(res)
 	return res
 }
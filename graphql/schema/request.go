@@ -17,7 +17,9 @@
 package schema
 
 import (
+	"encoding/json"
 	"net/http"
+	"strconv"
 
 	"github.com/pkg/errors"
 
@@ -60,6 +62,135 @@ func (s *schema) Operation(req *Request) (Operation, error) {
 		return nil, gqlErr
 	}
 
+	return s.operation(doc, req.Query, req.Variables, req.OperationName, req.Header)
+}
+
+// OperationFromDoc is like Operation, but for a query that was already parsed into doc, e.g. a
+// persisted query. doc is still validated against s. The operation is built from a copy of doc,
+// so the same doc can be used by any number of operations at once.
+func (s *schema) OperationFromDoc(doc *ast.QueryDocument, vars map[string]interface{},
+	opName string) (Operation, error) {
+	if doc == nil || len(doc.Operations) == 0 {
+		return nil, errors.New("no query document supplied")
+	}
+
+	var query string
+	if pos := doc.Operations[0].Position; pos != nil && pos.Src != nil {
+		query = pos.Src.Input
+	}
+	return s.operation(copyQueryDocument(doc), query, vars, opName, nil)
+}
+
+// copyQueryDocument returns a deep copy of the operations and fragments in doc. Validating a
+// document and expanding its fragments write to it, so a document that's shared, e.g. a cached
+// persisted query, has to be copied first.
+func copyQueryDocument(doc *ast.QueryDocument) *ast.QueryDocument {
+	cp := *doc
+	cp.Operations = make(ast.OperationList, 0, len(doc.Operations))
+	for _, op := range doc.Operations {
+		opCp := *op
+		opCp.VariableDefinitions = copyVariableDefinitions(op.VariableDefinitions)
+		opCp.Directives = copyDirectives(op.Directives)
+		opCp.SelectionSet = copySelectionSet(op.SelectionSet)
+		cp.Operations = append(cp.Operations, &opCp)
+	}
+	cp.Fragments = make(ast.FragmentDefinitionList, 0, len(doc.Fragments))
+	for _, frag := range doc.Fragments {
+		fragCp := *frag
+		fragCp.VariableDefinition = copyVariableDefinitions(frag.VariableDefinition)
+		fragCp.Directives = copyDirectives(frag.Directives)
+		fragCp.SelectionSet = copySelectionSet(frag.SelectionSet)
+		cp.Fragments = append(cp.Fragments, &fragCp)
+	}
+	return &cp
+}
+
+func copySelectionSet(selSet ast.SelectionSet) ast.SelectionSet {
+	if selSet == nil {
+		return nil
+	}
+	cp := make(ast.SelectionSet, 0, len(selSet))
+	for _, sel := range selSet {
+		switch sel := sel.(type) {
+		case *ast.Field:
+			f := *sel
+			f.Arguments = copyArguments(sel.Arguments)
+			f.Directives = copyDirectives(sel.Directives)
+			f.SelectionSet = copySelectionSet(sel.SelectionSet)
+			cp = append(cp, &f)
+		case *ast.InlineFragment:
+			frag := *sel
+			frag.Directives = copyDirectives(sel.Directives)
+			frag.SelectionSet = copySelectionSet(sel.SelectionSet)
+			cp = append(cp, &frag)
+		case *ast.FragmentSpread:
+			// Definition is pointed at the copied fragment when the copy is validated.
+			spread := *sel
+			spread.Directives = copyDirectives(sel.Directives)
+			cp = append(cp, &spread)
+		}
+	}
+	return cp
+}
+
+func copyVariableDefinitions(defs ast.VariableDefinitionList) ast.VariableDefinitionList {
+	if defs == nil {
+		return nil
+	}
+	cp := make(ast.VariableDefinitionList, 0, len(defs))
+	for _, def := range defs {
+		defCp := *def
+		defCp.DefaultValue = copyValue(def.DefaultValue)
+		defCp.Directives = copyDirectives(def.Directives)
+		cp = append(cp, &defCp)
+	}
+	return cp
+}
+
+func copyDirectives(dirs ast.DirectiveList) ast.DirectiveList {
+	if dirs == nil {
+		return nil
+	}
+	cp := make(ast.DirectiveList, 0, len(dirs))
+	for _, dir := range dirs {
+		dirCp := *dir
+		dirCp.Arguments = copyArguments(dir.Arguments)
+		cp = append(cp, &dirCp)
+	}
+	return cp
+}
+
+func copyArguments(args ast.ArgumentList) ast.ArgumentList {
+	if args == nil {
+		return nil
+	}
+	cp := make(ast.ArgumentList, 0, len(args))
+	for _, arg := range args {
+		argCp := *arg
+		argCp.Value = copyValue(arg.Value)
+		cp = append(cp, &argCp)
+	}
+	return cp
+}
+
+func copyValue(val *ast.Value) *ast.Value {
+	if val == nil {
+		return nil
+	}
+	cp := *val
+	if val.Children != nil {
+		cp.Children = make(ast.ChildValueList, 0, len(val.Children))
+		for _, child := range val.Children {
+			childCp := *child
+			childCp.Value = copyValue(child.Value)
+			cp.Children = append(cp.Children, &childCp)
+		}
+	}
+	return &cp
+}
+
+func (s *schema) operation(doc *ast.QueryDocument, query string, variables map[string]interface{},
+	opName string, header http.Header) (Operation, error) {
 	listErr := validator.Validate(s.schema, doc)
 	if len(listErr) != 0 {
 		return nil, listErr
@@ -71,26 +202,27 @@ func (s *schema) Operation(req *Request) (Operation, error) {
 			"fields defined for subscription operation.")
 	}
 
-	if len(doc.Operations) > 1 && req.OperationName == "" {
+	if len(doc.Operations) > 1 && opName == "" {
 		return nil, errors.Errorf("Operation name must by supplied when query has more " +
 			"than 1 operation.")
 	}
 
-	op := doc.Operations.ForName(req.OperationName)
+	op := doc.Operations.ForName(opName)
 	if op == nil {
 		return nil, errors.Errorf("Supplied operation name %s isn't present in the request.",
-			req.OperationName)
+			opName)
 	}
 
-	vars, gqlErr := validator.VariableValues(s.schema, op, req.Variables)
+	vars, gqlErr := validator.VariableValues(s.schema, op,
+		normalizeIDVariables(s.schema, op, variables))
 	if gqlErr != nil {
 		return nil, gqlErr
 	}
 
 	operation := &operation{op: op,
 		vars:                    vars,
-		query:                   req.Query,
-		header:                  req.Header,
+		query:                   query,
+		header:                  header,
 		doc:                     doc,
 		inSchema:                s,
 		interfaceImplFragFields: map[*ast.Field]string{},
@@ -104,6 +236,116 @@ func (s *schema) Operation(req *Request) (Operation, error) {
 	return operation, nil
 }
 
+// normalizeIDVariables returns vars with the numeric values given for ID fields converted to
+// strings. That covers variables of type ID, or a list of ID, as well as ID fields at any depth
+// inside variables of input types, e.g. the id filter of a $filter: PostFilter. Such values come
+// from JSON numbers in the request, as json.Number or float64, and IDArgValue can only parse IDs
+// given as strings. vars itself isn't modified.
+func normalizeIDVariables(sch *ast.Schema, op *ast.OperationDefinition,
+	vars map[string]interface{}) map[string]interface{} {
+	var normalized map[string]interface{}
+	for _, def := range op.VariableDefinitions {
+		val, ok := vars[def.Variable]
+		if !ok {
+			continue
+		}
+		newVal, changed := normalizeIDValue(sch, def.Type, val)
+		if !changed {
+			continue
+		}
+		if normalized == nil {
+			normalized = make(map[string]interface{}, len(vars))
+			for k, v := range vars {
+				normalized[k] = v
+			}
+		}
+		normalized[def.Variable] = newVal
+	}
+
+	if normalized == nil {
+		return vars
+	}
+	return normalized
+}
+
+// normalizeIDValue converts the numeric values for ID fields in val, which is a value of type
+// typ, to strings. The values that contain a converted value are copied, rather than modified.
+// It also returns whether anything had to be converted.
+func normalizeIDValue(sch *ast.Schema, typ *ast.Type, val interface{}) (interface{}, bool) {
+	if typ.Elem != nil {
+		list, ok := val.([]interface{})
+		if !ok {
+			// a single value is accepted for a list, see input coercion in the GraphQL spec
+			return normalizeIDValue(sch, typ.Elem, val)
+		}
+		var newList []interface{}
+		for i, elem := range list {
+			newElem, changed := normalizeIDValue(sch, typ.Elem, elem)
+			if !changed {
+				continue
+			}
+			if newList == nil {
+				newList = make([]interface{}, len(list))
+				copy(newList, list)
+			}
+			newList[i] = newElem
+		}
+		if newList == nil {
+			return val, false
+		}
+		return newList, true
+	}
+
+	if typ.NamedType == IDType {
+		return idToString(val)
+	}
+
+	def := sch.Types[typ.NamedType]
+	obj, ok := val.(map[string]interface{})
+	if def == nil || def.Kind != ast.InputObject || !ok {
+		return val, false
+	}
+	var newObj map[string]interface{}
+	for _, fld := range def.Fields {
+		fldVal, ok := obj[fld.Name]
+		if !ok {
+			continue
+		}
+		newFldVal, changed := normalizeIDValue(sch, fld.Type, fldVal)
+		if !changed {
+			continue
+		}
+		if newObj == nil {
+			newObj = make(map[string]interface{}, len(obj))
+			for k, v := range obj {
+				newObj[k] = v
+			}
+		}
+		newObj[fld.Name] = newFldVal
+	}
+	if newObj == nil {
+		return val, false
+	}
+	return newObj, true
+}
+
+// idToString converts a numeric ID value to a string. It also returns whether the value had to
+// be converted.
+func idToString(val interface{}) (interface{}, bool) {
+	switch v := val.(type) {
+	case json.Number:
+		return v.String(), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case int:
+		return strconv.Itoa(v), true
+	default:
+		return val, false
+	}
+}
+
 // recursivelyExpandFragmentSelections puts a fragment's selection set directly inside this
 // field's selection set, and does it recursively for all the fields in this field's selection
 // set. This eventually expands all the fragment references anywhere in the hierarchy.
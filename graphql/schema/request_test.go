@@ -0,0 +1,311 @@
+/*
+ * Copyright 2020 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/dgraph-io/gqlparser/v2/ast"
+	"github.com/dgraph-io/gqlparser/v2/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperationWithMultipleOperations(t *testing.T) {
+	sch := schemaFromString(t, `
+	type Post {
+		id: ID!
+		title: String
+	}`)
+
+	query := `
+	query getPosts {
+		queryPost { title }
+	}
+
+	mutation addPosts {
+		addPost(input: [{title: "A"}]) { numUids }
+	}`
+
+	tcases := []struct {
+		name          string
+		operationName string
+		isQuery       bool
+		isMutation    bool
+		field         string
+		err           string
+	}{
+		{
+			name:          "selects the query by name",
+			operationName: "getPosts",
+			isQuery:       true,
+			field:         "queryPost",
+		},
+		{
+			name:          "selects the mutation by name",
+			operationName: "addPosts",
+			isMutation:    true,
+			field:         "addPost",
+		},
+		{
+			name: "errors without an operation name",
+			err:  "Operation name must by supplied when query has more than 1 operation.",
+		},
+		{
+			name:          "errors for an unknown operation name",
+			operationName: "deletePosts",
+			err:           "Supplied operation name deletePosts isn't present in the request.",
+		},
+	}
+
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			op, err := sch.Operation(&Request{Query: query, OperationName: tcase.operationName})
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.isQuery, op.IsQuery())
+			require.Equal(t, tcase.isMutation, op.IsMutation())
+
+			var names []string
+			for _, q := range op.Queries() {
+				names = append(names, q.Name())
+			}
+			for _, m := range op.Mutations() {
+				names = append(names, m.Name())
+			}
+			require.Equal(t, []string{tcase.field}, names)
+		})
+	}
+}
+
+func TestOperationNameAndRawQuery(t *testing.T) {
+	sch := schemaFromString(t, `
+	type Post {
+		id: ID!
+		title: String
+	}`)
+
+	query := `query getPosts($first: Int) {
+		queryPost(first: $first) { title }
+	}
+
+	query countPosts {
+		aggregatePost { count }
+	}`
+
+	op, err := sch.Operation(&Request{Query: query, OperationName: "countPosts"})
+	require.NoError(t, err)
+	require.Equal(t, "countPosts", op.Name())
+	require.Equal(t, query, op.RawQuery())
+
+	anonymous := `{ queryPost { title } }`
+	op, err = sch.Operation(&Request{Query: anonymous})
+	require.NoError(t, err)
+	require.Equal(t, "", op.Name())
+	require.Equal(t, anonymous, op.RawQuery())
+}
+
+func TestOperationWithNumericIDVariables(t *testing.T) {
+	sch := schemaFromString(t, `
+	type Post {
+		id: ID!
+		title: String
+	}`)
+
+	// Variables are decoded with UseNumber, like the GraphQL HTTP handler does.
+	var largeIDVars map[string]interface{}
+	d := json.NewDecoder(strings.NewReader(`{"id": 18446744073709551615}`))
+	d.UseNumber()
+	require.NoError(t, d.Decode(&largeIDVars))
+
+	tcases := []struct {
+		name string
+		vars map[string]interface{}
+		uid  uint64
+	}{
+		{name: "large ID as json.Number", vars: largeIDVars, uid: 18446744073709551615},
+		{name: "ID as float64", vars: map[string]interface{}{"id": float64(26)}, uid: 26},
+		{name: "ID as string", vars: map[string]interface{}{"id": "0x1a"}, uid: 26},
+	}
+
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			op, err := sch.Operation(&Request{
+				Query:     `query($id: ID!) { getPost(id: $id) { title } }`,
+				Variables: tcase.vars,
+			})
+			require.NoError(t, err)
+			_, uid, err := op.Queries()[0].IDArgValue()
+			require.NoError(t, err)
+			require.Equal(t, tcase.uid, uid)
+		})
+	}
+
+	// The variables in the request are left as they were.
+	require.Equal(t, json.Number("18446744073709551615"), largeIDVars["id"])
+}
+
+func TestOperationFromDoc(t *testing.T) {
+	sch := schemaFromString(t, `
+	type Post {
+		id: ID!
+		title: String
+	}`)
+
+	query := `query getPosts($first: Int) {
+		queryPost(first: $first) { ...postFrag }
+	}
+	fragment postFrag on Post { id title }`
+
+	doc, gqlErr := parser.ParseQuery(&ast.Source{Input: query})
+	require.Nil(t, gqlErr)
+
+	// The same doc is used for operations with different variables, as for a persisted query.
+	for _, first := range []int{1, 2} {
+		vars := map[string]interface{}{"first": first}
+		fromDoc, err := sch.OperationFromDoc(doc, vars, "getPosts")
+		require.NoError(t, err)
+		fromStr, err := sch.Operation(&Request{Query: query, Variables: vars})
+		require.NoError(t, err)
+
+		require.Equal(t, fromStr.Name(), fromDoc.Name())
+		require.Equal(t, fromStr.RawQuery(), fromDoc.RawQuery())
+		require.True(t, fromDoc.IsQuery())
+		require.Len(t, fromDoc.Queries(), 1)
+		strQuery, docQuery := fromStr.Queries()[0], fromDoc.Queries()[0]
+		require.Equal(t, strQuery.Name(), docQuery.Name())
+		require.Equal(t, strQuery.ArgValue("first"), docQuery.ArgValue("first"))
+		require.Len(t, docQuery.SelectionSet(), len(strQuery.SelectionSet()))
+		for i, f := range strQuery.SelectionSet() {
+			require.Equal(t, f.Name(), docQuery.SelectionSet()[i].Name())
+		}
+	}
+
+	// The fragment spread in doc hasn't been expanded in place.
+	queryPost := doc.Operations[0].SelectionSet[0].(*ast.Field)
+	require.Len(t, queryPost.SelectionSet, 1)
+	require.IsType(t, &ast.FragmentSpread{}, queryPost.SelectionSet[0])
+
+	// The document is still validated against the schema.
+	doc, gqlErr = parser.ParseQuery(&ast.Source{Input: `query { queryPost { text } }`})
+	require.Nil(t, gqlErr)
+	_, err := sch.OperationFromDoc(doc, nil, "")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Cannot query field \"text\" on type \"Post\".")
+
+	_, err = sch.OperationFromDoc(nil, nil, "")
+	require.EqualError(t, err, "no query document supplied")
+}
+
+func TestNormalize(t *testing.T) {
+	sch := schemaFromString(t, `
+	type Post {
+		id: ID!
+		title: String
+		text: String
+		author: Author
+	}
+
+	type Author {
+		id: ID!
+		name: String
+	}`)
+
+	op, err := sch.Operation(&Request{
+		Query: `query($withText: Boolean!, $withAuthor: Boolean!) {
+			queryPost {
+				id @skip(if: true)
+				title @include(if: true)
+				text @include(if: $withText)
+				author @include(if: $withAuthor) {
+					name @skip(if: false)
+				}
+			}
+		}`,
+		Variables: map[string]interface{}{"withText": false, "withAuthor": true},
+	})
+	require.NoError(t, err)
+
+	norm := op.Normalize()
+	posts := norm.Queries()[0]
+	var names []string
+	posts.WalkSelections(func(path string, f Field) {
+		names = append(names, path)
+		require.Empty(t, f.(*field).field.Directives, path)
+	})
+	require.Equal(t, []string{"queryPost.title", "queryPost.author", "queryPost.author.name"},
+		names)
+
+	// The original operation still has all its fields and directives.
+	sel := op.Queries()[0].SelectionSet()
+	require.Len(t, sel, 4)
+	require.True(t, sel[0].Skip())
+	require.False(t, sel[2].Include())
+}
+
+func TestOperationWithNestedNumericIDVariables(t *testing.T) {
+	sch := schemaFromString(t, `
+	type Post {
+		id: ID!
+		title: String
+	}
+
+	type Author {
+		id: ID!
+		name: String
+		posts: [Post]
+	}`)
+
+	vars := map[string]interface{}{
+		"filter": map[string]interface{}{
+			"id":  []interface{}{json.Number("18446744073709551615"), float64(2)},
+			"not": map[string]interface{}{"id": []interface{}{"0x3"}},
+		},
+	}
+	op, err := sch.Operation(&Request{
+		Query:     `query($filter: PostFilter) { queryPost(filter: $filter) { title } }`,
+		Variables: vars,
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"id":  []interface{}{"18446744073709551615", "2"},
+		"not": map[string]interface{}{"id": []interface{}{"0x3"}},
+	}, op.Queries()[0].ArgValue("filter"))
+	// The variables in the request are left as they were.
+	require.Equal(t, float64(2), vars["filter"].(map[string]interface{})["id"].([]interface{})[1])
+
+	op, err = sch.Operation(&Request{
+		Query: `mutation($input: [AddAuthorInput!]!) {
+			addAuthor(input: $input) { numUids }
+		}`,
+		Variables: map[string]interface{}{
+			"input": []interface{}{map[string]interface{}{
+				"name":  "A",
+				"posts": []interface{}{map[string]interface{}{"id": float64(5)}},
+			}},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{map[string]interface{}{
+		"name":  "A",
+		"posts": []interface{}{map[string]interface{}{"id": "5"}},
+	}}, op.Mutations()[0].ArgValue("input"))
+}
@@ -1987,21 +1987,107 @@ func customDirectiveValidation(sch *ast.Schema,
 	return errs
 }
 
+// idValidation checks the type of an @id field, and the optional index argument of @id. That
+// argument picks between a hash and an exact index for String! fields. Using it along with a
+// @search argument for the other one of those indexes is rejected by searchValidation.
 func idValidation(sch *ast.Schema,
 	typ *ast.Definition,
 	field *ast.FieldDefinition,
 	dir *ast.Directive,
 	secrets map[string]x.SensitiveByteSlice) gqlerror.List {
-	if field.Type.String() == "String!" ||
-		field.Type.String() == "Int!" ||
-		field.Type.String() == "Int64!" ||
-		field.Type.String() == "Float!" {
+	if field.Type.String() != "String!" &&
+		field.Type.String() != "Int!" &&
+		field.Type.String() != "Int64!" &&
+		field.Type.String() != "Float!" {
+		return []*gqlerror.Error{gqlerror.ErrorPosf(
+			dir.Position,
+			"Type %s; Field %s: with @id directive must be of type String!, Int!, Int64! or Float!, not %s",
+			typ.Name, field.Name, field.Type.String())}
+	}
+
+	arg := dir.Arguments.ForName(idIndexArg)
+	if arg == nil {
 		return nil
 	}
-	return []*gqlerror.Error{gqlerror.ErrorPosf(
-		dir.Position,
-		"Type %s; Field %s: with @id directive must be of type String!, Int!, Int64! or Float!, not %s",
-		typ.Name, field.Name, field.Type.String())}
+	if field.Type.String() != "String!" {
+		return []*gqlerror.Error{gqlerror.ErrorPosf(
+			arg.Position,
+			"Type %s; Field %s: argument %s of @id directive can only be used with fields of "+
+				"type String!, not %s", typ.Name, field.Name, idIndexArg, field.Type.String())}
+	}
+	if arg.Value.Raw != "hash" && arg.Value.Raw != "exact" {
+		return []*gqlerror.Error{gqlerror.ErrorPosf(
+			arg.Position,
+			"Type %s; Field %s: argument %s of @id directive must be either hash or exact, "+
+				"not %s", typ.Name, field.Name, idIndexArg, arg.Value.Raw)}
+	}
+	return nil
+}
+
+func embeddingValidation(sch *ast.Schema,
+	typ *ast.Definition,
+	field *ast.FieldDefinition,
+	dir *ast.Directive,
+	secrets map[string]x.SensitiveByteSlice) gqlerror.List {
+	if field.Type.Elem == nil || field.Type.Elem.Elem != nil || field.Type.Name() != "Float" {
+		return []*gqlerror.Error{gqlerror.ErrorPosf(
+			dir.Position,
+			"Type %s; Field %s: with @embedding directive must be of type [Float!] or [Float], "+
+				"not %s", typ.Name, field.Name, field.Type.String())}
+	}
+
+	arg := dir.Arguments.ForName(embeddingDimensionsArg)
+	if arg == nil {
+		return nil
+	}
+	if dims, err := strconv.Atoi(arg.Value.Raw); err != nil || dims <= 0 {
+		return []*gqlerror.Error{gqlerror.ErrorPosf(
+			arg.Position,
+			"Type %s; Field %s: argument %s of @embedding directive must be a positive Int, "+
+				"not %s", typ.Name, field.Name, embeddingDimensionsArg, arg.Value.Raw)}
+	}
+	return nil
+}
+
+func remoteResponseValidation(sch *ast.Schema,
+	typ *ast.Definition,
+	field *ast.FieldDefinition,
+	dir *ast.Directive,
+	secrets map[string]x.SensitiveByteSlice) gqlerror.List {
+	if typ.Directives.ForName(remoteDirective) == nil {
+		return []*gqlerror.Error{gqlerror.ErrorPosf(
+			dir.Position,
+			"Type %s; Field %s: @remoteResponse directive can only be defined on fields of "+
+				"@remote types.", typ.Name, field.Name)}
+	}
+
+	arg := dir.Arguments.ForName(remoteResponseNameArg)
+	if arg == nil || arg.Value.Raw == "" {
+		return []*gqlerror.Error{gqlerror.ErrorPosf(
+			dir.Position,
+			"Type %s; Field %s: argument %s of @remoteResponse directive must be a non-empty "+
+				"String.", typ.Name, field.Name, remoteResponseNameArg)}
+	}
+	return nil
+}
+
+func complexityValidation(sch *ast.Schema,
+	typ *ast.Definition,
+	field *ast.FieldDefinition,
+	dir *ast.Directive,
+	secrets map[string]x.SensitiveByteSlice) gqlerror.List {
+	arg := dir.Arguments.ForName(complexityValueArg)
+	if arg == nil {
+		// GraphQL validation has already reported the missing argument.
+		return nil
+	}
+	if cost, err := strconv.Atoi(arg.Value.Raw); err != nil || cost < 0 {
+		return []*gqlerror.Error{gqlerror.ErrorPosf(
+			arg.Position,
+			"Type %s; Field %s: argument %s of @complexity directive must be a non-negative Int, "+
+				"not %s", typ.Name, field.Name, complexityValueArg, arg.Value.Raw)}
+	}
+	return nil
 }
 
 func apolloKeyValidation(sch *ast.Schema, typ *ast.Definition) gqlerror.List {
@@ -0,0 +1,198 @@
+/*
+ * Copyright 2020 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"sort"
+
+	"github.com/dgraph-io/dgraph/x"
+	"github.com/dgraph-io/gqlparser/v2/ast"
+	"github.com/pkg/errors"
+)
+
+// SchemaChangeKind is the kind of a change found between two versions of a GraphQL schema.
+type SchemaChangeKind string
+
+const (
+	TypeAdded            SchemaChangeKind = "TypeAdded"
+	TypeRemoved          SchemaChangeKind = "TypeRemoved"
+	FieldAdded           SchemaChangeKind = "FieldAdded"
+	FieldRemoved         SchemaChangeKind = "FieldRemoved"
+	TypeChanged          SchemaChangeKind = "TypeChanged"
+	NullabilityTightened SchemaChangeKind = "NullabilityTightened"
+	PredicateRemapped    SchemaChangeKind = "PredicateRemapped"
+)
+
+// SchemaChange is a single change found between two versions of a GraphQL schema.
+// FieldName is empty for changes to a whole type. Breaking is true if the change can break
+// existing clients or make existing data inaccessible.
+type SchemaChange struct {
+	Kind      SchemaChangeKind
+	TypeName  string
+	FieldName string
+	Breaking  bool
+}
+
+// DiffSchema compares the user defined types of two schemas and returns the changes needed to
+// go from oldSch to newSch. Only the types and fields that get stored in Dgraph are compared,
+// the generated types (filters, payloads, etc.) follow from those. Both schemas must have been
+// built by this package, e.g. with FromString, otherwise an error is returned.
+//
+// The following changes are reported as breaking:
+//  * removing a type or a field
+//  * adding a non-nullable field
+//  * changing the type of a field, e.g. from String to Int or from [Post] to Post
+//  * making a nullable field, or the elements of a list field, non-nullable
+//  * mapping a field to a different Dgraph predicate
+func DiffSchema(oldSch, newSch Schema) ([]SchemaChange, error) {
+	o, ok := oldSch.(*schema)
+	if !ok {
+		return nil, errors.Errorf("can't diff a schema of type %T", oldSch)
+	}
+	n, ok := newSch.(*schema)
+	if !ok {
+		return nil, errors.Errorf("can't diff a schema of type %T", newSch)
+	}
+
+	oldTypes := userTypeNames(o)
+	newTypes := userTypeNames(n)
+
+	var changes []SchemaChange
+	for _, name := range oldTypes {
+		if !x.HasString(newTypes, name) {
+			changes = append(changes, SchemaChange{Kind: TypeRemoved, TypeName: name,
+				Breaking: true})
+			continue
+		}
+		changes = append(changes, diffFields(o, n, name)...)
+	}
+	for _, name := range newTypes {
+		if !x.HasString(oldTypes, name) {
+			changes = append(changes, SchemaChange{Kind: TypeAdded, TypeName: name})
+		}
+	}
+	return changes, nil
+}
+
+// userTypeNames returns the sorted names of the types in s that are stored in Dgraph.
+func userTypeNames(s *schema) []string {
+	generated := generatedTypeNames(s)
+	var names []string
+	for name := range s.dgraphPredicate {
+		if generated[name] || s.schema.Types[name].Directives.ForName(remoteDirective) != nil {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// generatedTypeNames returns the names of the object types generated for the types in s: the
+// payloads of the add, update and delete mutations, and the results of the aggregate queries and
+// aggregate fields.
+func generatedTypeNames(s *schema) map[string]bool {
+	names := make(map[string]bool)
+	if s.schema.Mutation != nil {
+		for _, fld := range s.schema.Mutation.Fields {
+			switch mutationType(fld.Name, s.customDirectives["Mutation"][fld.Name]) {
+			case AddMutation, UpdateMutation, DeleteMutation:
+				names[fld.Type.Name()] = true
+			}
+		}
+	}
+	if s.schema.Query != nil {
+		for _, fld := range s.schema.Query.Fields {
+			if queryType(fld.Name, s.customDirectives["Query"][fld.Name]) == AggregateQuery {
+				names[fld.Type.Name()] = true
+			}
+		}
+	}
+	for _, def := range s.schema.Types {
+		for _, fld := range def.Fields {
+			if isGeneratedAggregateField(def, fld) {
+				names[fld.Type.Name()] = true
+			}
+		}
+	}
+	return names
+}
+
+func diffFields(o, n *schema, typeName string) []SchemaChange {
+	oldDef := o.schema.Types[typeName]
+	newDef := n.schema.Types[typeName]
+
+	var changes []SchemaChange
+	for _, oldFld := range oldDef.Fields {
+		if isGeneratedAggregateField(oldDef, oldFld) {
+			continue
+		}
+		newFld := newDef.Fields.ForName(oldFld.Name)
+		if newFld == nil {
+			changes = append(changes, SchemaChange{Kind: FieldRemoved, TypeName: typeName,
+				FieldName: oldFld.Name, Breaking: true})
+			continue
+		}
+		switch changed, tightened := compareTypes(oldFld.Type, newFld.Type); {
+		case changed:
+			changes = append(changes, SchemaChange{Kind: TypeChanged, TypeName: typeName,
+				FieldName: oldFld.Name, Breaking: true})
+		case tightened:
+			changes = append(changes, SchemaChange{Kind: NullabilityTightened,
+				TypeName: typeName, FieldName: oldFld.Name, Breaking: true})
+		}
+		if o.dgraphPredicate[typeName][oldFld.Name] != n.dgraphPredicate[typeName][newFld.Name] {
+			changes = append(changes, SchemaChange{Kind: PredicateRemapped, TypeName: typeName,
+				FieldName: oldFld.Name, Breaking: true})
+		}
+	}
+	for _, newFld := range newDef.Fields {
+		if isGeneratedAggregateField(newDef, newFld) || oldDef.Fields.ForName(newFld.Name) != nil {
+			continue
+		}
+		changes = append(changes, SchemaChange{Kind: FieldAdded, TypeName: typeName,
+			FieldName: newFld.Name, Breaking: newFld.Type.NonNull})
+	}
+	return changes
+}
+
+// compareTypes tells whether a field's type changed from oldTyp to newTyp, i.e. it names another
+// type or has a different list nesting. If it didn't, it also tells whether the type, or the
+// element type of a list, went from nullable to non-nullable.
+func compareTypes(oldTyp, newTyp *ast.Type) (changed, tightened bool) {
+	if (oldTyp.Elem == nil) != (newTyp.Elem == nil) || oldTyp.NamedType != newTyp.NamedType {
+		return true, false
+	}
+	tightened = !oldTyp.NonNull && newTyp.NonNull
+	if oldTyp.Elem != nil {
+		changed, elemTightened := compareTypes(oldTyp.Elem, newTyp.Elem)
+		return changed, tightened || elemTightened
+	}
+	return false, tightened
+}
+
+// isGeneratedAggregateField tells whether fld is the <field>Aggregate field that is generated
+// in def for a list field of def, see addAggregateFields.
+func isGeneratedAggregateField(def *ast.Definition, fld *ast.FieldDefinition) bool {
+	for _, listFld := range def.Fields {
+		if isTypeList(listFld) && fld.Name == listFld.Name+"Aggregate" &&
+			fld.Type.Name() == listFld.Type.Name()+"AggregateResult" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+/*
+ * Copyright 2020 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffSchema(t *testing.T) {
+	oldSch := schemaFromString(t, `
+	type Author {
+		id: ID!
+		name: String
+		country: String
+		rating: String
+		favourites: [Post]
+		posts: [Post]
+	}
+
+	type Post {
+		id: ID!
+		title: String
+		tags: [String]
+	}
+
+	type Country @remote {
+		code: String
+	}`)
+
+	newSch := schemaFromString(t, `
+	type Author {
+		id: ID!
+		name: String!
+		rating: Int
+		favourites: Post
+		posts: [Post]
+	}
+
+	type Post {
+		id: ID!
+		title: String @dgraph(pred: "post_title")
+		tags: [String!]
+		text: String
+	}
+
+	type Comment {
+		id: ID!
+		text: String
+	}
+
+	type DeleteRequestPayload {
+		id: ID!
+		reason: String
+	}
+
+	type StatsAggregateResult {
+		id: ID!
+		total: Int
+	}
+
+	type Country @remote {
+		code: String
+		name: String
+	}`)
+
+	changes, err := DiffSchema(oldSch, newSch)
+	require.NoError(t, err)
+	require.Equal(t, []SchemaChange{
+		{Kind: NullabilityTightened, TypeName: "Author", FieldName: "name", Breaking: true},
+		{Kind: FieldRemoved, TypeName: "Author", FieldName: "country", Breaking: true},
+		{Kind: TypeChanged, TypeName: "Author", FieldName: "rating", Breaking: true},
+		{Kind: TypeChanged, TypeName: "Author", FieldName: "favourites", Breaking: true},
+		{Kind: PredicateRemapped, TypeName: "Post", FieldName: "title", Breaking: true},
+		{Kind: NullabilityTightened, TypeName: "Post", FieldName: "tags", Breaking: true},
+		{Kind: FieldAdded, TypeName: "Post", FieldName: "text", Breaking: false},
+		{Kind: TypeAdded, TypeName: "Comment", Breaking: false},
+		{Kind: TypeAdded, TypeName: "DeleteRequestPayload", Breaking: false},
+		{Kind: TypeAdded, TypeName: "StatsAggregateResult", Breaking: false},
+	}, changes)
+
+	changes, err = DiffSchema(oldSch, oldSch)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	_, err = DiffSchema(oldSch, nil)
+	require.Error(t, err)
+}
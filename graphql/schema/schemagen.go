@@ -625,7 +625,9 @@ func genDgSchema(gqlSch *ast.Schema, definitions []string) string {
 							indexes = append(indexes, "int")
 						case "Float":
 							indexes = append(indexes, "float")
-						case "String", "ID":
+						case "String":
+							indexes = append(indexes, idIndex(f))
+						case "ID":
 							indexes = append(indexes, "hash")
 						}
 					}
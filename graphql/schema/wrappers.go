@@ -18,13 +18,18 @@ package schema
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"reflect"
 	"sort"
 	"strconv"
 	"strings"
+	"unicode"
 
 	"github.com/dgraph-io/dgraph/graphql/authorization"
 
@@ -74,6 +79,26 @@ type FieldHTTPConfig struct {
 	GraphqlBatchModeArgument string
 }
 
+// GenConfig reports which queries, mutations and subscriptions are generated for a type, as
+// configured through the @generate directive. Everything except subscriptions is generated by
+// default.
+type GenConfig struct {
+	GetQuery       bool
+	FilterQuery    bool
+	PasswordQuery  bool
+	AggregateQuery bool
+	AddMutation    bool
+	UpdateMutation bool
+	DeleteMutation bool
+	Subscription   bool
+}
+
+// Argument is the name and the value of an argument of a field.
+type Argument struct {
+	Name  string
+	Value interface{}
+}
+
 // Query/Mutation types and arg names
 const (
 	GetQuery             QueryType    = "get"
@@ -98,11 +123,56 @@ const (
 // Schema represents a valid GraphQL schema
 type Schema interface {
 	Operation(r *Request) (Operation, error)
+	// OperationFromDoc builds the operation named opName from an already parsed query document.
+	OperationFromDoc(doc *ast.QueryDocument, vars map[string]interface{},
+		opName string) (Operation, error)
 	Queries(t QueryType) []string
 	Mutations(t MutationType) []string
 	IsFederated() bool
 	SetMeta(meta *metaInfo)
 	Meta() *metaInfo
+	// RequiredJWTClaims returns the sorted names of all the JWT claims used by the auth rules
+	// in the schema.
+	RequiredJWTClaims() []string
+	// DetectInputCycles returns the cycles formed by the non-null, non-list fields of the input
+	// types in the schema. An input having such a cycle can never be supplied in a request.
+	DetectInputCycles() []string
+	// IsAuthRequired tells whether the type with the given name has an @auth rule for the
+	// mutations of type op.
+	IsAuthRequired(typeName string, op MutationType) bool
+	// Implementers returns the sorted Dgraph type names of the object types implementing the
+	// interface with the given name.
+	Implementers(interfaceName string) []string
+	// PasswordPredicate returns the Dgraph predicate storing the @secret field of the type with
+	// the given name, or an empty string if the type doesn't have a @secret field.
+	PasswordPredicate(typeName string) string
+	// AuthFieldRulesAll returns a copy of the map from field name to the @auth rules on the
+	// fields of the type with the given name. Fields without @auth aren't in the map.
+	AuthFieldRulesAll(typeName string) map[string]*AuthContainer
+	// GraphQLTypeName returns the name of the GraphQL object type stored in Dgraph with the
+	// given type name, or an empty string if no object type is stored with that name.
+	GraphQLTypeName(dgraphType string) string
+	// FieldDefinitionForType returns the definition of the field with the given name in the
+	// GraphQL object type stored in Dgraph with the given type name. It returns nil if there
+	// is no such type, or the type doesn't have that field.
+	FieldDefinitionForType(dgraphType, fieldName string) FieldDefinition
+	// Fingerprint returns a hex encoded SHA-256 hash of the types, fields and directives in the
+	// schema. It doesn't depend on the formatting or comments of the schema text, so it only
+	// changes when the schema itself changes.
+	Fingerprint() string
+	// MutatedTypes returns the sorted names of the types that have an add, update or delete
+	// mutation in the schema.
+	MutatedTypes() []string
+	// AuthControlledTypes returns the sorted names of the types that have @auth rules, either on
+	// the type or on any of its fields.
+	AuthControlledTypes() []string
+	// EnumTypes returns the names of the values of each enum defined by the user, keyed by the
+	// name of the enum. Enums that are built in, or generated from other types (like
+	// TOrderable and THasFilter), are left out.
+	EnumTypes() map[string][]string
+	// ReverseEdges returns the Dgraph predicate of each reverse edge in the schema, i.e. fields
+	// with @dgraph(pred: "~..."), mapped to the predicate of the forward edge that it reverses.
+	ReverseEdges() map[string]string
 }
 
 // An Operation is a single valid GraphQL operation.  It contains either
@@ -115,6 +185,14 @@ type Operation interface {
 	IsMutation() bool
 	IsSubscription() bool
 	CacheControl() string
+	// Name returns the name of the operation, or an empty string for an anonymous operation.
+	Name() string
+	// RawQuery returns the query string of the request the operation was parsed from.
+	RawQuery() string
+	// Normalize returns a copy of the operation without the fields excluded by @skip or
+	// @include, given the variables of the operation. The @skip and @include directives are
+	// removed from the fields that remain. The operation itself isn't changed.
+	Normalize() Operation
 }
 
 // A Field is one field from an Operation.
@@ -125,10 +203,25 @@ type Field interface {
 	DgraphAlias() string
 	ResponseName() string
 	Arguments() map[string]interface{}
+	// OrderedArguments returns the arguments of the field, with any variables resolved, in the
+	// order they were given in the query. Arguments that weren't given, but have a value (e.g.
+	// a default value), follow in the order of their definition.
+	OrderedArguments() []Argument
 	ArgValue(name string) interface{}
+	// CanMergeWith tells whether this field and other have the same name, arguments and type, so
+	// that their selection sets can be merged when they have the same response name.
+	CanMergeWith(other Field) bool
 	IsArgListType(name string) bool
+	// IsArgListAtPath tells whether the value at path is a list. The first element of path is
+	// the name of an argument and the rest are the names of the fields nested inside its input
+	// type, e.g.: ["input", "set", "tags"].
+	IsArgListAtPath(path []string) bool
 	IDArgValue() (*string, uint64, error)
 	XIDArg() string
+	// XIDArgValue returns the Dgraph predicate of the @id field of the field's type along with
+	// the value given for it in the arguments. Both are empty if the type doesn't have an @id
+	// field, or the node is looked up by its ID instead. It is an error if neither was given.
+	XIDArgValue() (predicate string, value string, err error)
 	SetArgTo(arg string, val interface{})
 	Skip() bool
 	Include() bool
@@ -139,6 +232,9 @@ type Field interface {
 	//  * seenField: used for skipping when the field has already been seen at the current level
 	SkipField(dgraphTypes []string, seenField map[string]bool) bool
 	Cascade() []string
+	// ValidateCascadeFields checks that each field listed in @cascade(fields: [...]) is also
+	// selected under this field. The list, or its elements, can be given through variables.
+	ValidateCascadeFields() error
 	// CustomRequiredFields returns a map from DgraphAlias to the field definition of the fields
 	// which are required to resolve this custom field.
 	CustomRequiredFields() map[string]FieldDefinition
@@ -150,14 +246,37 @@ type Field interface {
 	Type() Type
 	IsExternal() bool
 	SelectionSet() []Field
+	// HasSelections tells whether anything is selected under this field, without building the
+	// SelectionSet() slice. Fragments count as selections.
+	HasSelections() bool
+	// WalkSelections recursively visits every field selected under this field, calling fn with
+	// the dotted path of response names from this field down to the visited field.
+	// For e.g.: queryPost.author.name
+	WalkSelections(fn func(path string, f Field))
+	// RequiresUID tells whether the type of this field is an object, interface or union, and so
+	// the uid of the node has to be fetched along with its selection set.
+	RequiresUID() bool
 	Location() x.Location
 	DgraphPredicate() string
 	Operation() Operation
 	// AbstractType tells us whether this field represents a GraphQL Interface/Union.
 	AbstractType() bool
 	IncludeAbstractField(types []string) bool
+	// IsTypename tells whether this is the __typename meta field, with or without an alias.
+	IsTypename() bool
 	TypeName(dgraphTypes []string) string
+	// ConcreteType returns the object type of a node with the given dgraph.type values, as
+	// returned in the response for a field having an interface or union type. It returns nil if
+	// none of the values is the Dgraph type of an object type.
+	ConcreteType(dgraphTypes []interface{}) Type
+	// DgraphTypesToFetch returns the Dgraph types of the field's type and of the interfaces it
+	// implements. If the field has an interface or union type, the types which could be returned
+	// for it are also included, so that TypeName can tell them apart in the response.
+	DgraphTypesToFetch() []string
 	GetObjectName() string
+	// ParentDgraphType returns the Dgraph type name of the object this field is defined in,
+	// taking @dgraph(type: ...) into account.
+	ParentDgraphType() string
 	IsAuthQuery() bool
 	CustomHTTPConfig() (*FieldHTTPConfig, error)
 	EnumValues() []string
@@ -166,6 +285,8 @@ type Field interface {
 	DgraphPredicateForAggregateField() string
 	IsAggregateField() bool
 	GqlErrorf(path []interface{}, message string, args ...interface{}) *x.GqlError
+	// Errorf returns a GraphQL error located at the position of the field in the query.
+	Errorf(format string, args ...interface{}) error
 	// MaxPathLength finds the max length (including list indexes) of any path in the 'query' f.
 	MaxPathLength() int
 	// PreAllocatePathSlice is used to pre-allocate a path buffer of the correct size before running
@@ -196,6 +317,13 @@ type Mutation interface {
 	Field
 	MutationType() MutationType
 	MutatedType() Type
+	// PayloadType returns the type returned by the mutation, e.g. AddPostPayload for addPost.
+	PayloadType() Type
+	// UpdateInputType and RemoveInputType return the types of the set and remove fields in the
+	// input of an update mutation, e.g. PostPatch for updatePost. They return nil for other
+	// mutations.
+	UpdateInputType() Type
+	RemoveInputType() Type
 	QueryField() Field
 	NumUidsField() Field
 }
@@ -209,6 +337,9 @@ type Query interface {
 	KeyField(typeName string) (string, bool, error)
 	BuildType(typeName string) Type
 	AuthFor(typ Type, jwtVars map[string]interface{}) Query
+	// IsLiveQueryable tells whether a query from a subscription operation can be resolved as a
+	// Dgraph live query. If it can't, the returned error describes why.
+	IsLiveQueryable() (bool, error)
 }
 
 // A Type is a GraphQL type like: Float, T, T! and [T!]!.  If it's not a list, then
@@ -217,6 +348,9 @@ type Query interface {
 type Type interface {
 	Field(name string) FieldDefinition
 	Fields() []FieldDefinition
+	// ScalarLeaves returns the fields of the type that have a scalar or an enum type, or a list
+	// of those. Fields referring to other types aren't included.
+	ScalarLeaves() []FieldDefinition
 	IDField() FieldDefinition
 	XIDField() FieldDefinition
 	InterfaceImplHasAuthRules() bool
@@ -239,6 +373,12 @@ type Type interface {
 	IsGeo() bool
 	IsAggregateResult() bool
 	IsInbuiltOrEnumType() bool
+	// ScalarKind returns the name of the scalar this type holds values of, e.g. "Int64" or
+	// "DateTime", if it is one of the scalars known to Dgraph. That includes ID, whose values
+	// are the uids of nodes. It is "" for any other type, including the geo types like Point,
+	// which are object types.
+	ScalarKind() string
+	GeneratedOps() GenConfig
 	fmt.Stringer
 }
 
@@ -255,11 +395,33 @@ type FieldDefinition interface {
 	IsExternal() bool
 	HasIDDirective() bool
 	Inverse() FieldDefinition
+	// InversePredicate returns the Dgraph predicate of the field given in @hasInverse, or ""
+	// if this field has no inverse.
+	InversePredicate() string
 	WithMemberType(string) FieldDefinition
 	// TODO - It might be possible to get rid of ForwardEdge and just use Inverse() always.
 	ForwardEdge() FieldDefinition
 	// GetAuthMeta returns the Dgraph.Authorization meta information stored in schema
 	GetAuthMeta() *authorization.AuthMeta
+	// IsVector tells whether this field stores a vector embedding, i.e., it has @embedding.
+	IsVector() bool
+	// VectorDimensions returns the number of dimensions given in @embedding(dimensions: ...),
+	// or 0 if this isn't a vector field or the dimensions weren't specified.
+	VectorDimensions() int
+	// RemoteResponseKey returns the key used for this field in the JSON returned by a remote
+	// API. It is given by @remoteResponse(name: ...), or is the field name if that is absent.
+	RemoteResponseKey() string
+	// Complexity returns the cost of resolving this field for one node, as given by
+	// @complexity(value: ...). It is 1 if the field doesn't have @complexity.
+	Complexity() int
+	// IDIndexType returns the Dgraph index of this @id field, either "hash" (the default) or
+	// "exact" if given as @id(index: "exact"). It is empty if the field doesn't have @id.
+	IDIndexType() string
+	// DefaultSearchTokenizer returns the Dgraph tokenizer used for this field when it has
+	// @search without any arguments, e.g.: "term" for String, "int" for Int and "year" for
+	// DateTime. For a String @id field, it is the index of the @id field. It is empty for
+	// fields that can't be searched.
+	DefaultSearchTokenizer() string
 }
 
 type astType struct {
@@ -293,6 +455,10 @@ type schema struct {
 	lambdaDirectives map[string]map[string]bool
 	// Map from typename to auth rules
 	authRules map[string]*TypeAuth
+	// passwordFields maps the name of a type to the field given by @secret on it. Types without
+	// @secret aren't in the map. It is pre-computed so that logins don't have to scan the
+	// directives of the type each time.
+	passwordFields map[string]*ast.FieldDefinition
 	// meta is the meta information extracted from input schema
 	meta *metaInfo
 }
@@ -324,6 +490,9 @@ type field struct {
 	// this flag has already been calculated or not. If not calculated, it would be nil.
 	// Otherwise, it would always contain a boolean value.
 	hasCustomHTTPChild *bool
+	// typ caches the result of Type(), as that gets called many times for a field while
+	// rewriting it. It is nil until Type() is called for the first time.
+	typ *astType
 }
 
 type fieldDefinition struct {
@@ -374,6 +543,295 @@ func (s *schema) Meta() *metaInfo {
 	return s.meta
 }
 
+func (s *schema) RequiredJWTClaims() []string {
+	claims := make(map[string]bool)
+	for _, typAuth := range s.authRules {
+		if typAuth == nil {
+			continue
+		}
+		typAuth.Rules.collectJWTClaims(claims)
+		for _, fldAuth := range typAuth.Fields {
+			fldAuth.collectJWTClaims(claims)
+		}
+	}
+
+	result := make([]string, 0, len(claims))
+	for claim := range claims {
+		result = append(result, claim)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func (s *schema) Implementers(interfaceName string) []string {
+	var names []string
+	for _, def := range s.schema.Types {
+		if def.Kind != ast.Object || !x.HasString(def.Interfaces, interfaceName) {
+			continue
+		}
+		// The type name could have been overwritten using @dgraph(type: ...)
+		names = append(names, typeName(def))
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (s *schema) PasswordPredicate(name string) string {
+	fd := s.passwordFields[name]
+	if fd == nil {
+		return ""
+	}
+	return s.dgraphPredicate[name][fd.Name]
+}
+
+func (s *schema) AuthControlledTypes() []string {
+	var names []string
+	for _, def := range s.schema.Types {
+		typAuth := s.authRules[typeName(def)]
+		if typAuth == nil || (typAuth.Rules == nil && len(typAuth.Fields) == 0) {
+			continue
+		}
+		names = append(names, def.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (s *schema) EnumTypes() map[string][]string {
+	enums := make(map[string][]string)
+	for name, def := range s.schema.Types {
+		if def.Kind != ast.Enum || def.BuiltIn || isGeneratedEnum(s.schema, def) {
+			continue
+		}
+		values := make([]string, 0, len(def.EnumValues))
+		for _, val := range def.EnumValues {
+			values = append(values, val.Name)
+		}
+		enums[name] = values
+	}
+	return enums
+}
+
+func (s *schema) ReverseEdges() map[string]string {
+	edges := make(map[string]string)
+	for _, preds := range s.dgraphPredicate {
+		for _, pred := range preds {
+			if strings.HasPrefix(pred, "~") || strings.HasPrefix(pred, "<~") {
+				edges[pred] = strings.Trim(pred, "<~>")
+			}
+		}
+	}
+	return edges
+}
+
+// isGeneratedEnum tells whether the enum def is one of the enums that we add to every schema,
+// or one that is generated for another type: TOrderable and THasFilter for an object or
+// interface T, and UType for a union U.
+func isGeneratedEnum(sch *ast.Schema, def *ast.Definition) bool {
+	switch def.Name {
+	case "DgraphIndex", "HTTPMethod", "Mode":
+		return true
+	}
+
+	for suffix, kinds := range map[string][]ast.DefinitionKind{
+		"Orderable": {ast.Object, ast.Interface},
+		"HasFilter": {ast.Object, ast.Interface},
+		"Type":      {ast.Union},
+	} {
+		if !strings.HasSuffix(def.Name, suffix) {
+			continue
+		}
+		base := sch.Types[strings.TrimSuffix(def.Name, suffix)]
+		for _, kind := range kinds {
+			if base != nil && base.Kind == kind {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (s *schema) AuthFieldRulesAll(name string) map[string]*AuthContainer {
+	def := s.schema.Types[name]
+	if def == nil {
+		return nil
+	}
+	typAuth := s.authRules[typeName(def)]
+	if typAuth == nil {
+		return nil
+	}
+
+	fields := make(map[string]*AuthContainer, len(typAuth.Fields))
+	for fld, rules := range typAuth.Fields {
+		fields[fld] = rules
+	}
+	return fields
+}
+
+func (s *schema) GraphQLTypeName(dgraphType string) string {
+	for _, typ := range s.typeNameAst[dgraphType] {
+		if typ.Kind == ast.Object {
+			return typ.Name
+		}
+	}
+	return ""
+}
+
+func (s *schema) FieldDefinitionForType(dgraphType, fieldName string) FieldDefinition {
+	name := s.GraphQLTypeName(dgraphType)
+	if name == "" || s.schema.Types[name].Fields.ForName(fieldName) == nil {
+		return nil
+	}
+	typ := &astType{
+		typ:             &ast.Type{NamedType: name},
+		inSchema:        s,
+		dgraphPredicate: s.dgraphPredicate,
+	}
+	return typ.Field(fieldName)
+}
+
+func (s *schema) MutatedTypes() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for mutName, typ := range s.mutatedType {
+		if s.customDirectives["Mutation"][mutName] != nil || seen[typ.Name()] {
+			continue
+		}
+		seen[typ.Name()] = true
+		names = append(names, typ.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Fingerprint hashes the types in order of their names. Fields, arguments and directives are
+// hashed in the order they were defined, as that order shows up in introspection.
+func (s *schema) Fingerprint() string {
+	names := make([]string, 0, len(s.schema.Types))
+	for name := range s.schema.Types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	h := sha256.New()
+	for _, name := range names {
+		def := s.schema.Types[name]
+		fmt.Fprintf(h, "%s %s %v %v\n", def.Kind, def.Name, def.Interfaces, def.Types)
+		writeDirectives(h, def.Directives)
+		for _, val := range def.EnumValues {
+			fmt.Fprintf(h, "\tvalue %s\n", val.Name)
+			writeDirectives(h, val.Directives)
+		}
+		for _, fld := range def.Fields {
+			fmt.Fprintf(h, "\tfield %s: %s\n", fld.Name, fld.Type)
+			for _, arg := range fld.Arguments {
+				fmt.Fprintf(h, "\t\targ %s: %s = %s\n", arg.Name, arg.Type, arg.DefaultValue)
+			}
+			writeDirectives(h, fld.Directives)
+			// @custom and @lambda were moved out of the field's directives by AsSchema.
+			if custom := s.customDirectives[name][fld.Name]; custom != nil {
+				writeDirectives(h, ast.DirectiveList{custom})
+			}
+			if s.lambdaDirectives[name][fld.Name] {
+				fmt.Fprintf(h, "\t@%s\n", lambdaDirective)
+			}
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func writeDirectives(w io.Writer, dirs ast.DirectiveList) {
+	for _, dir := range dirs {
+		fmt.Fprintf(w, "\t@%s", dir.Name)
+		for _, arg := range dir.Arguments {
+			fmt.Fprintf(w, " %s: %s", arg.Name, arg.Value)
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// IsAuthRequired doesn't take closed by default auth into account, that depends on the
+// authorization metadata the rules get evaluated with.
+func (s *schema) IsAuthRequired(name string, op MutationType) bool {
+	def := s.schema.Types[name]
+	if def == nil {
+		return false
+	}
+	typAuth := s.authRules[typeName(def)]
+	if typAuth == nil || typAuth.Rules == nil {
+		return false
+	}
+
+	switch op {
+	case AddMutation:
+		return typAuth.Rules.Add != nil
+	case UpdateMutation:
+		return typAuth.Rules.Update != nil
+	case DeleteMutation:
+		return typAuth.Rules.Delete != nil
+	default:
+		return false
+	}
+}
+
+// DetectInputCycles reports each cycle as the path of fields forming it, for example:
+//
+//	AInput.b -> BInput.a -> AInput
+//
+// Nullable and list fields break a cycle, as those can be left out or given as empty lists.
+func (s *schema) DetectInputCycles() []string {
+	var inputs []string
+	for name, def := range s.schema.Types {
+		if def.Kind == ast.InputObject {
+			inputs = append(inputs, name)
+		}
+	}
+	sort.Strings(inputs)
+
+	const (
+		white = iota // not visited yet
+		grey         // on the current path
+		black        // done, along with all the input types reachable from it
+	)
+	color := make(map[string]int)
+	// pathIndex maps the grey input types to the index of their field in path.
+	pathIndex := make(map[string]int)
+	var cycles, path []string
+	var visit func(name string)
+	visit = func(name string) {
+		color[name] = grey
+		pathIndex[name] = len(path)
+		for _, fld := range s.schema.Types[name].Fields {
+			if !fld.Type.NonNull || fld.Type.Elem != nil {
+				continue
+			}
+			next := s.schema.Types[fld.Type.Name()]
+			if next == nil || next.Kind != ast.InputObject {
+				continue
+			}
+
+			path = append(path, name+"."+fld.Name)
+			switch color[next.Name] {
+			case grey:
+				cycle := append(append([]string{}, path[pathIndex[next.Name]:]...), next.Name)
+				cycles = append(cycles, strings.Join(cycle, " -> "))
+			case white:
+				visit(next.Name)
+			}
+			path = path[:len(path)-1]
+		}
+		delete(pathIndex, name)
+		color[name] = black
+	}
+
+	for _, name := range inputs {
+		if color[name] == white {
+			visit(name)
+		}
+	}
+	return cycles
+}
+
 func (o *operation) IsQuery() bool {
 	return o.op.Operation == ast.Query
 }
@@ -425,6 +883,70 @@ func (o *operation) CacheControl() string {
 	return "public,max-age=" + o.op.Directives.ForName(cacheControlDirective).Arguments[0].Value.Raw
 }
 
+func (o *operation) Name() string {
+	return o.op.Name
+}
+
+func (o *operation) RawQuery() string {
+	return o.query
+}
+
+func (o *operation) Normalize() Operation {
+	op := *o.op
+	norm := &operation{
+		op:                      &op,
+		vars:                    o.vars,
+		header:                  o.header,
+		interfaceImplFragFields: make(map[*ast.Field]string),
+		query:                   o.query,
+		doc:                     o.doc,
+		inSchema:                o.inSchema,
+	}
+	op.SelectionSet = norm.normalizeSelections(o.op.SelectionSet, o.interfaceImplFragFields)
+	return norm
+}
+
+// normalizeSelections copies the fields in sel which aren't skipped, recording the copies of
+// the fields found in fragFields in o.interfaceImplFragFields.
+func (o *operation) normalizeSelections(sel ast.SelectionSet,
+	fragFields map[*ast.Field]string) ast.SelectionSet {
+	if sel == nil {
+		return nil
+	}
+
+	result := make(ast.SelectionSet, 0, len(sel))
+	for _, s := range sel {
+		// Fragments were expanded into fields when the operation was created.
+		f, ok := s.(*ast.Field)
+		if !ok {
+			continue
+		}
+		if skip := f.Directives.ForName("skip"); skip != nil &&
+			skip.ArgumentMap(o.vars)["if"].(bool) {
+			continue
+		}
+		if include := f.Directives.ForName("include"); include != nil &&
+			!include.ArgumentMap(o.vars)["if"].(bool) {
+			continue
+		}
+
+		fld := *f
+		fld.Arguments = append(ast.ArgumentList(nil), f.Arguments...)
+		fld.Directives = nil
+		for _, dir := range f.Directives {
+			if dir.Name != "skip" && dir.Name != "include" {
+				fld.Directives = append(fld.Directives, dir)
+			}
+		}
+		fld.SelectionSet = o.normalizeSelections(f.SelectionSet, fragFields)
+		if typ, ok := fragFields[f]; ok {
+			o.interfaceImplFragFields[&fld] = typ
+		}
+		result = append(result, &fld)
+	}
+	return result
+}
+
 // parentInterface returns the name of an interface that a field belonging to a type definition
 // typDef inherited from. If there is no such interface, then it returns an empty string.
 //
@@ -648,6 +1170,21 @@ func typeMappings(s *ast.Schema) map[string][]*ast.Definition {
 	return typeNameAst
 }
 
+func passwordMappings(s *ast.Schema) map[string]*ast.FieldDefinition {
+	passwordFields := make(map[string]*ast.FieldDefinition)
+
+	for _, typ := range s.Types {
+		if typ.Kind != ast.Object && typ.Kind != ast.Interface {
+			continue
+		}
+		if fd := getPasswordField(typ); fd != nil {
+			passwordFields[typ.Name] = fd
+		}
+	}
+
+	return passwordFields
+}
+
 // customAndLambdaMappings does following things:
 // * If there is @custom on any field, it removes the directive from the list of directives on
 //	 that field. Instead, it puts it in a map of typeName->fieldName->custom directive definition.
@@ -878,9 +1415,16 @@ func AsSchema(s *ast.Schema) (Schema, error) {
 		customDirectives: customDirs,
 		lambdaDirectives: lambdaDirs,
 		authRules:        authRules,
+		passwordFields:   passwordMappings(s),
 		meta:             &metaInfo{}, // initialize with an empty metaInfo
 	}
 	sch.mutatedType = mutatedTypeMapping(sch, dgraphPredicate)
+	for _, typAuth := range authRules {
+		typAuth.Rules.setSchema(sch)
+		for _, fldAuth := range typAuth.Fields {
+			fldAuth.setSchema(sch)
+		}
+	}
 
 	return sch, nil
 }
@@ -940,6 +1484,10 @@ func (f *field) GqlErrorf(path []interface{}, message string, args ...interface{
 	}
 }
 
+func (f *field) Errorf(format string, args ...interface{}) error {
+	return x.GqlErrorf(format, args...).WithLocations(f.Location())
+}
+
 func (f *field) MaxPathLength() int {
 	childMax := 0
 	for _, child := range f.SelectionSet() {
@@ -1031,10 +1579,37 @@ func (f *field) Arguments() map[string]interface{} {
 	return f.arguments
 }
 
+func (f *field) OrderedArguments() []Argument {
+	args := f.Arguments()
+	result := make([]Argument, 0, len(args))
+	seen := make(map[string]bool, len(args))
+	add := func(name string) {
+		if val, ok := args[name]; ok && !seen[name] {
+			seen[name] = true
+			result = append(result, Argument{Name: name, Value: val})
+		}
+	}
+
+	for _, arg := range f.field.Arguments {
+		add(arg.Name)
+	}
+	if f.field.Definition != nil {
+		for _, arg := range f.field.Definition.Arguments {
+			add(arg.Name)
+		}
+	}
+	return result
+}
+
 func (f *field) ArgValue(name string) interface{} {
 	return f.Arguments()[name]
 }
 
+func (f *field) CanMergeWith(other Field) bool {
+	return f.Name() == other.Name() && f.Type().String() == other.Type().String() &&
+		reflect.DeepEqual(f.Arguments(), other.Arguments())
+}
+
 func (f *field) IsArgListType(name string) bool {
 	arg := f.field.Arguments.ForName(name)
 	if arg == nil {
@@ -1044,6 +1619,30 @@ func (f *field) IsArgListType(name string) bool {
 	return arg.Value.ExpectedType.Elem != nil
 }
 
+func (f *field) IsArgListAtPath(path []string) bool {
+	if len(path) == 0 || f.field.Definition == nil {
+		return false
+	}
+	arg := f.field.Definition.Arguments.ForName(path[0])
+	if arg == nil {
+		return false
+	}
+
+	typ := arg.Type
+	for _, name := range path[1:] {
+		def := f.op.inSchema.schema.Types[typ.Name()]
+		if def == nil || def.Kind != ast.InputObject {
+			return false
+		}
+		fld := def.Fields.ForName(name)
+		if fld == nil {
+			return false
+		}
+		typ = fld.Type
+	}
+	return typ.Elem != nil
+}
+
 func (f *field) Skip() bool {
 	dir := f.field.Directives.ForName("skip")
 	if dir == nil {
@@ -1103,6 +1702,53 @@ func (f *field) Cascade() []string {
 	return fields
 }
 
+func (f *field) ValidateCascadeFields() error {
+	dir := f.field.Directives.ForName(cascadeDirective)
+	if dir == nil {
+		return nil
+	}
+	arg := dir.Arguments.ForName(cascadeArg)
+	if arg == nil || arg.Value == nil {
+		return nil
+	}
+
+	selected := make(map[string]bool)
+	for _, child := range f.SelectionSet() {
+		// A field counts as selected even if it is selected under an alias.
+		selected[child.Name()] = true
+		selected[child.ResponseName()] = true
+	}
+
+	// The fields are given as a list, whose elements can be variables, or as a variable holding
+	// the whole list.
+	fields := arg.Value.Children
+	if arg.Value.Kind != ast.ListValue {
+		fields = ast.ChildValueList{{Value: arg.Value}}
+	}
+	for _, child := range fields {
+		val, err := child.Value.Value(f.op.vars)
+		if err != nil {
+			return err
+		}
+		names, ok := val.([]interface{})
+		if !ok {
+			names = []interface{}{val}
+		}
+		for _, name := range names {
+			fieldName, _ := name.(string)
+			if name == nil || selected[fieldName] {
+				continue
+			}
+			return x.GqlErrorf("Field `%s` given in @cascade is not selected in `%s`.",
+				fieldName, f.ResponseName()).WithLocations(x.Location{
+				Line:   child.Value.Position.Line,
+				Column: child.Value.Position.Column,
+			})
+		}
+	}
+	return nil
+}
+
 func toRequiredFieldDefs(requiredFieldNames map[string]bool, sibling *field) map[string]FieldDefinition {
 	res := make(map[string]FieldDefinition, len(requiredFieldNames))
 	parentType := &astType{
@@ -1244,6 +1890,32 @@ func (f *field) XIDArg() string {
 	return f.Type().DgraphPredicate(xidArgName)
 }
 
+func (f *field) XIDArgValue() (string, string, error) {
+	xidField := f.Type().XIDField()
+	if xidField == nil {
+		return "", "", nil
+	}
+
+	xid, _, err := f.IDArgValue()
+	if err != nil {
+		return "", "", err
+	}
+	if xid != nil {
+		return f.XIDArg(), *xid, nil
+	}
+
+	idField := f.Type().IDField()
+	if idField == nil {
+		return "", "", f.Errorf("%s requires a value for the @id field %s of type %s",
+			f.Name(), xidField.Name(), f.Type().Name())
+	}
+	if f.ArgValue(idField.Name()) == nil {
+		return "", "", f.Errorf("%s requires a value for either the ID field %s or the @id "+
+			"field %s of type %s", f.Name(), idField.Name(), xidField.Name(), f.Type().Name())
+	}
+	return "", "", nil
+}
+
 func (f *field) IDArgValue() (xid *string, uid uint64, err error) {
 	idField := f.Type().IDField()
 	passwordField := f.Type().PasswordField()
@@ -1268,10 +1940,9 @@ func (f *field) IDArgValue() (xid *string, uid uint64, err error) {
 		case string:
 			xidArgVal = v
 		default:
-			pos := f.field.GetPosition()
 			if !ok {
-				err = x.GqlErrorf("Argument (%s) of %s was not able to be parsed as a string",
-					xidArgName, f.Name()).WithLocations(x.Location{Line: pos.Line, Column: pos.Column})
+				err = f.Errorf("Argument (%s) of %s was not able to be parsed as a string",
+					xidArgName, f.Name())
 				return
 			}
 		}
@@ -1290,9 +1961,7 @@ func (f *field) IDArgValue() (xid *string, uid uint64, err error) {
 		uid, ierr = strconv.ParseUint(id, 0, 64)
 
 		if !ok || ierr != nil {
-			pos := f.field.GetPosition()
-			err = x.GqlErrorf("ID argument (%s) of %s was not able to be parsed", id, f.Name()).
-				WithLocations(x.Location{Line: pos.Line, Column: pos.Column})
+			err = f.Errorf("ID argument (%s) of %s was not able to be parsed", id, f.Name())
 			return
 		}
 	}
@@ -1311,6 +1980,10 @@ func (q *query) BuildType(typeName string) Type {
 }
 
 func (f *field) Type() Type {
+	if f.typ != nil {
+		return f.typ
+	}
+
 	var t *ast.Type
 	if f.field != nil && f.field.Definition != nil {
 		t = f.field.Definition.Type
@@ -1329,11 +2002,12 @@ func (f *field) Type() Type {
 		t = &ast.Type{NamedType: "__Undefined__", NonNull: false}
 	}
 
-	return &astType{
+	f.typ = &astType{
 		typ:             t,
 		inSchema:        f.op.inSchema,
 		dgraphPredicate: f.op.inSchema.dgraphPredicate,
 	}
+	return f.typ
 }
 
 func isAbstractKind(kind ast.DefinitionKind) bool {
@@ -1348,11 +2022,28 @@ func (f *field) GetObjectName() string {
 	return f.field.ObjectDefinition.Name
 }
 
+func (f *field) ParentDgraphType() string {
+	return typeName(f.field.ObjectDefinition)
+}
+
 func (t *astType) IsInbuiltOrEnumType() bool {
 	_, ok := inbuiltTypeToDgraph[t.Name()]
 	return ok || (t.inSchema.schema.Types[t.Name()].Kind == ast.Enum)
 }
 
+func (t *astType) ScalarKind() string {
+	def := t.inSchema.schema.Types[t.Name()]
+	if def == nil || def.Kind != ast.Scalar {
+		// the geo types are in inbuiltTypeToDgraph, but they are objects
+		return ""
+	}
+	if _, ok := inbuiltTypeToDgraph[t.Name()]; !ok {
+		// scalars like _Any that Dgraph doesn't store
+		return ""
+	}
+	return t.Name()
+}
+
 func getCustomHTTPConfig(f *field, isQueryOrMutation bool) (*FieldHTTPConfig, error) {
 	custom := f.op.inSchema.customDirectives[f.GetObjectName()][f.Name()]
 	httpArg := custom.Arguments.ForName(httpArg)
@@ -1480,6 +2171,30 @@ func (f *field) SelectionSet() (flds []Field) {
 	return
 }
 
+func (f *field) HasSelections() bool {
+	return len(f.field.SelectionSet) > 0
+}
+
+func (f *field) WalkSelections(fn func(path string, f Field)) {
+	walkSelections(f, f.ResponseName(), fn)
+}
+
+func walkSelections(f Field, prefix string, fn func(path string, f Field)) {
+	for _, child := range f.SelectionSet() {
+		path := prefix + "." + child.ResponseName()
+		fn(path, child)
+		walkSelections(child, path, fn)
+	}
+}
+
+func (f *field) RequiresUID() bool {
+	def := f.op.inSchema.schema.Types[f.Type().Name()]
+	if def == nil {
+		return false
+	}
+	return def.Kind == ast.Object || isAbstractKind(def.Kind)
+}
+
 func (f *field) Location() x.Location {
 	return x.Location{
 		Line:   f.field.Position.Line,
@@ -1496,15 +2211,48 @@ func (f *field) DgraphPredicate() string {
 
 func (f *field) TypeName(dgraphTypes []string) string {
 	for _, typ := range dgraphTypes {
-		for _, origTyp := range f.op.inSchema.typeNameAst[typ] {
-			if origTyp.Kind != ast.Object {
-				continue
+		if name := f.op.inSchema.GraphQLTypeName(typ); name != "" {
+			return name
+		}
+	}
+	return f.GetObjectName()
+}
+
+func (f *field) ConcreteType(dgraphTypes []interface{}) Type {
+	for _, typ := range dgraphTypes {
+		dgType, ok := typ.(string)
+		if !ok {
+			continue
+		}
+		if name := f.op.inSchema.GraphQLTypeName(dgType); name != "" {
+			return &astType{
+				typ:             &ast.Type{NamedType: name},
+				inSchema:        f.op.inSchema,
+				dgraphPredicate: f.op.inSchema.dgraphPredicate,
 			}
-			return origTyp.Name
 		}
+	}
+	return nil
+}
 
+func (f *field) DgraphTypesToFetch() []string {
+	var types []string
+	addType := func(t Type) {
+		for _, name := range append([]string{t.DgraphName()}, t.Interfaces()...) {
+			if !x.HasString(types, name) {
+				types = append(types, name)
+			}
+		}
 	}
-	return f.GetObjectName()
+
+	typ := f.Type()
+	addType(typ)
+	if typ.IsInterface() || typ.IsUnion() {
+		for _, impl := range typ.ImplementingTypes() {
+			addType(impl)
+		}
+	}
+	return types
 }
 
 func (f *field) IncludeAbstractField(dgraphTypes []string) bool {
@@ -1537,7 +2285,7 @@ func (f *field) IncludeAbstractField(dgraphTypes []string) bool {
 				//	 include the queried field if it is of ID type.
 				// * If the field exists in the map corresponding to the object type
 				_, ok = f.op.inSchema.dgraphPredicate[origTyp.Name][f.Name()]
-				return ok || f.Type().Name() == IDType || f.Name() == Typename
+				return ok || f.Type().Name() == IDType || f.IsTypename()
 			}
 		}
 
@@ -1545,6 +2293,12 @@ func (f *field) IncludeAbstractField(dgraphTypes []string) bool {
 	return false
 }
 
+// IsTypename looks at the name of the field and not at its alias, so an aliased __typename,
+// like kind: __typename, is also the __typename field.
+func (f *field) IsTypename() bool {
+	return f.Name() == Typename
+}
+
 func (q *query) IsAuthQuery() bool {
 	return (*field)(q).field.Arguments.ForName("dgraph.uid") != nil
 }
@@ -1557,6 +2311,10 @@ func (q *query) GqlErrorf(path []interface{}, message string, args ...interface{
 	return (*field)(q).GqlErrorf(path, message, args...)
 }
 
+func (q *query) Errorf(format string, args ...interface{}) error {
+	return (*field)(q).Errorf(format, args...)
+}
+
 func (q *query) MaxPathLength() int {
 	return (*field)(q).MaxPathLength()
 }
@@ -1618,6 +2376,14 @@ func (q *query) Arguments() map[string]interface{} {
 	return (*field)(q).Arguments()
 }
 
+func (q *query) OrderedArguments() []Argument {
+	return (*field)(q).OrderedArguments()
+}
+
+func (q *query) CanMergeWith(other Field) bool {
+	return (*field)(q).CanMergeWith(other)
+}
+
 func (q *query) ArgValue(name string) interface{} {
 	return (*field)(q).ArgValue(name)
 }
@@ -1626,6 +2392,10 @@ func (q *query) IsArgListType(name string) bool {
 	return (*field)(q).IsArgListType(name)
 }
 
+func (q *query) IsArgListAtPath(path []string) bool {
+	return (*field)(q).IsArgListAtPath(path)
+}
+
 func (q *query) Skip() bool {
 	return false
 }
@@ -1642,6 +2412,10 @@ func (q *query) Cascade() []string {
 	return (*field)(q).Cascade()
 }
 
+func (q *query) ValidateCascadeFields() error {
+	return (*field)(q).ValidateCascadeFields()
+}
+
 func (q *query) CustomRequiredFields() map[string]FieldDefinition {
 	return (*field)(q).CustomRequiredFields()
 }
@@ -1666,6 +2440,10 @@ func (q *query) XIDArg() string {
 	return (*field)(q).XIDArg()
 }
 
+func (q *query) XIDArgValue() (string, string, error) {
+	return (*field)(q).XIDArgValue()
+}
+
 func (q *query) Type() Type {
 	return (*field)(q).Type()
 }
@@ -1674,6 +2452,18 @@ func (q *query) SelectionSet() []Field {
 	return (*field)(q).SelectionSet()
 }
 
+func (q *query) HasSelections() bool {
+	return (*field)(q).HasSelections()
+}
+
+func (q *query) WalkSelections(fn func(path string, f Field)) {
+	(*field)(q).WalkSelections(fn)
+}
+
+func (q *query) RequiresUID() bool {
+	return (*field)(q).RequiresUID()
+}
+
 func (q *query) Location() x.Location {
 	return (*field)(q).Location()
 }
@@ -1686,6 +2476,10 @@ func (q *query) GetObjectName() string {
 	return q.field.ObjectDefinition.Name
 }
 
+func (q *query) ParentDgraphType() string {
+	return (*field)(q).ParentDgraphType()
+}
+
 func (q *query) CustomHTTPConfig() (*FieldHTTPConfig, error) {
 	return getCustomHTTPConfig((*field)(q), true)
 }
@@ -1821,6 +2615,25 @@ func (q *query) DQLQuery() string {
 	return ""
 }
 
+// IsLiveQueryable returns an error located at the first field that prevents q from being
+// resolved as a live query. Fields with @custom are resolved by calling remote endpoints, so
+// there is no way to get notified when their value changes.
+func (q *query) IsLiveQueryable() (bool, error) {
+	if !q.op.IsSubscription() {
+		return false, x.GqlErrorf("Query `%s` is not part of a subscription.", q.Name()).
+			WithLocations(q.Location())
+	}
+
+	var err error
+	q.WalkSelections(func(path string, f Field) {
+		if err == nil && f.IsCustomHTTP() {
+			err = x.GqlErrorf("Custom field `%s` is not supported in graphql subscription",
+				f.Name()).WithLocations(f.Location())
+		}
+	})
+	return err == nil, err
+}
+
 func queryType(name string, custom *ast.Directive) QueryType {
 	switch {
 	case custom != nil:
@@ -1861,10 +2674,22 @@ func (q *query) TypeName(dgraphTypes []string) string {
 	return (*field)(q).TypeName(dgraphTypes)
 }
 
+func (q *query) ConcreteType(dgraphTypes []interface{}) Type {
+	return (*field)(q).ConcreteType(dgraphTypes)
+}
+
+func (q *query) DgraphTypesToFetch() []string {
+	return (*field)(q).DgraphTypesToFetch()
+}
+
 func (q *query) IncludeAbstractField(dgraphTypes []string) bool {
 	return (*field)(q).IncludeAbstractField(dgraphTypes)
 }
 
+func (q *query) IsTypename() bool {
+	return (*field)(q).IsTypename()
+}
+
 func (m *mutation) Name() string {
 	return (*field)(m).Name()
 }
@@ -1885,14 +2710,26 @@ func (m *mutation) IsArgListType(name string) bool {
 	return (*field)(m).IsArgListType(name)
 }
 
+func (m *mutation) IsArgListAtPath(path []string) bool {
+	return (*field)(m).IsArgListAtPath(path)
+}
+
 func (m *mutation) Arguments() map[string]interface{} {
 	return (*field)(m).Arguments()
 }
 
+func (m *mutation) OrderedArguments() []Argument {
+	return (*field)(m).OrderedArguments()
+}
+
 func (m *mutation) ArgValue(name string) interface{} {
 	return (*field)(m).ArgValue(name)
 }
 
+func (m *mutation) CanMergeWith(other Field) bool {
+	return (*field)(m).CanMergeWith(other)
+}
+
 func (m *mutation) Skip() bool {
 	return false
 }
@@ -1909,6 +2746,10 @@ func (m *mutation) Cascade() []string {
 	return (*field)(m).Cascade()
 }
 
+func (m *mutation) ValidateCascadeFields() error {
+	return (*field)(m).ValidateCascadeFields()
+}
+
 func (m *mutation) CustomRequiredFields() map[string]FieldDefinition {
 	return (*field)(m).CustomRequiredFields()
 }
@@ -1937,6 +2778,10 @@ func (m *mutation) XIDArg() string {
 	return (*field)(m).XIDArg()
 }
 
+func (m *mutation) XIDArgValue() (string, string, error) {
+	return (*field)(m).XIDArgValue()
+}
+
 func (m *mutation) IDArgValue() (*string, uint64, error) {
 	return (*field)(m).IDArgValue()
 }
@@ -1945,9 +2790,21 @@ func (m *mutation) SelectionSet() []Field {
 	return (*field)(m).SelectionSet()
 }
 
+func (m *mutation) HasSelections() bool {
+	return (*field)(m).HasSelections()
+}
+
+func (m *mutation) WalkSelections(fn func(path string, f Field)) {
+	(*field)(m).WalkSelections(fn)
+}
+
+func (m *mutation) RequiresUID() bool {
+	return (*field)(m).RequiresUID()
+}
+
 func (m *mutation) QueryField() Field {
 	for _, f := range m.SelectionSet() {
-		if f.Name() == NumUid || f.Name() == Typename || f.Name() == Msg {
+		if f.Name() == NumUid || f.IsTypename() || f.Name() == Msg {
 			continue
 		}
 		// if @cascade was given on mutation itself, then it should get applied for the query which
@@ -1988,6 +2845,44 @@ func (m *mutation) MutatedType() Type {
 	return m.op.inSchema.mutatedType[m.Name()]
 }
 
+func (m *mutation) PayloadType() Type {
+	return (*field)(m).Type()
+}
+
+func (m *mutation) UpdateInputType() Type {
+	return m.updateInputFieldType("set")
+}
+
+func (m *mutation) RemoveInputType() Type {
+	return m.updateInputFieldType("remove")
+}
+
+// updateInputFieldType returns the type of the field with the given name in the input of an
+// update mutation.
+func (m *mutation) updateInputFieldType(name string) Type {
+	if m.MutationType() != UpdateMutation || m.field.Definition == nil {
+		return nil
+	}
+	arg := m.field.Definition.Arguments.ForName(InputArgName)
+	if arg == nil {
+		return nil
+	}
+	input := m.op.inSchema.schema.Types[arg.Type.Name()]
+	if input == nil {
+		return nil
+	}
+	fld := input.Fields.ForName(name)
+	if fld == nil {
+		return nil
+	}
+
+	return &astType{
+		typ:             fld.Type,
+		inSchema:        m.op.inSchema,
+		dgraphPredicate: m.op.inSchema.dgraphPredicate,
+	}
+}
+
 func (m *mutation) CustomHTTPConfig() (*FieldHTTPConfig, error) {
 	return getCustomHTTPConfig((*field)(m), true)
 }
@@ -2000,6 +2895,10 @@ func (m *mutation) GetObjectName() string {
 	return m.field.ObjectDefinition.Name
 }
 
+func (m *mutation) ParentDgraphType() string {
+	return (*field)(m).ParentDgraphType()
+}
+
 func (m *mutation) MutationType() MutationType {
 	return mutationType(m.Name(), m.op.inSchema.customDirectives["Mutation"][m.Name()])
 }
@@ -2031,10 +2930,22 @@ func (m *mutation) TypeName(dgraphTypes []string) string {
 	return (*field)(m).TypeName(dgraphTypes)
 }
 
+func (m *mutation) ConcreteType(dgraphTypes []interface{}) Type {
+	return (*field)(m).ConcreteType(dgraphTypes)
+}
+
+func (m *mutation) DgraphTypesToFetch() []string {
+	return (*field)(m).DgraphTypesToFetch()
+}
+
 func (m *mutation) IncludeAbstractField(dgraphTypes []string) bool {
 	return (*field)(m).IncludeAbstractField(dgraphTypes)
 }
 
+func (m *mutation) IsTypename() bool {
+	return (*field)(m).IsTypename()
+}
+
 func (m *mutation) IsAuthQuery() bool {
 	return (*field)(m).field.Arguments.ForName("dgraph.uid") != nil
 }
@@ -2047,6 +2958,10 @@ func (m *mutation) GqlErrorf(path []interface{}, message string, args ...interfa
 	return (*field)(m).GqlErrorf(path, message, args...)
 }
 
+func (m *mutation) Errorf(format string, args ...interface{}) error {
+	return (*field)(m).Errorf(format, args...)
+}
+
 func (m *mutation) MaxPathLength() int {
 	return (*field)(m).MaxPathLength()
 }
@@ -2075,6 +2990,26 @@ func (t *astType) AuthRules() *TypeAuth {
 	return t.inSchema.authRules[t.DgraphName()]
 }
 
+// GeneratedOps returns the operations that are generated for t, taking into account any
+// @generate directive on its definition.
+func (t *astType) GeneratedOps() GenConfig {
+	def := t.inSchema.schema.Types[t.Name()]
+	if def == nil {
+		return GenConfig{}
+	}
+	params := parseGenerateDirectiveParams(def)
+	return GenConfig{
+		GetQuery:       params.generateGetQuery,
+		FilterQuery:    params.generateFilterQuery,
+		PasswordQuery:  params.generatePasswordQuery,
+		AggregateQuery: params.generateAggregateQuery,
+		AddMutation:    params.generateAddMutation,
+		UpdateMutation: params.generateUpdateMutation,
+		DeleteMutation: params.generateDeleteMutation,
+		Subscription:   params.generateSubscription,
+	}
+}
+
 func (t *astType) IsGeo() bool {
 	return t.Name() == "Point" || t.Name() == "Polygon" || t.Name() == "MultiPolygon"
 }
@@ -2109,6 +3044,17 @@ func (t *astType) Fields() []FieldDefinition {
 	return result
 }
 
+func (t *astType) ScalarLeaves() []FieldDefinition {
+	var result []FieldDefinition
+	for _, fd := range t.Fields() {
+		def := t.inSchema.schema.Types[fd.Type().Name()]
+		if def != nil && (def.Kind == ast.Scalar || def.Kind == ast.Enum) {
+			result = append(result, fd)
+		}
+	}
+	return result
+}
+
 func (fd *fieldDefinition) Name() string {
 	return fd.fieldDef.Name
 }
@@ -2180,6 +3126,14 @@ func (fd *fieldDefinition) Inverse() FieldDefinition {
 	}
 }
 
+func (fd *fieldDefinition) InversePredicate() string {
+	inv := fd.Inverse()
+	if inv == nil {
+		return ""
+	}
+	return fd.dgraphPredicate[inv.ParentType().Name()][inv.Name()]
+}
+
 func (fd *fieldDefinition) WithMemberType(memberType string) FieldDefinition {
 	// just need to return a copy of this fieldDefinition with type set to memberType
 	return &fieldDefinition{
@@ -2251,6 +3205,62 @@ func (fd *fieldDefinition) GetAuthMeta() *authorization.AuthMeta {
 	return fd.inSchema.meta.authMeta
 }
 
+func (fd *fieldDefinition) IsVector() bool {
+	return fd.fieldDef.Directives.ForName(embeddingDirective) != nil
+}
+
+func (fd *fieldDefinition) VectorDimensions() int {
+	dir := fd.fieldDef.Directives.ForName(embeddingDirective)
+	if dir == nil {
+		return 0
+	}
+	arg := dir.Arguments.ForName(embeddingDimensionsArg)
+	if arg == nil {
+		return 0
+	}
+	// This can't error as the dimensions were validated during schema update.
+	dims, _ := strconv.Atoi(arg.Value.Raw)
+	return dims
+}
+
+func (fd *fieldDefinition) IDIndexType() string {
+	return idIndex(fd.fieldDef)
+}
+
+func (fd *fieldDefinition) DefaultSearchTokenizer() string {
+	typName := fd.fieldDef.Type.Name()
+	if idIdx := idIndex(fd.fieldDef); idIdx != "" && typName == "String" {
+		return idIdx
+	}
+	if search, ok := defaultSearches[typName]; ok {
+		return supportedSearches[search].dgIndex
+	}
+	if def := fd.inSchema.schema.Types[typName]; def != nil && def.Kind == ast.Enum {
+		// enums always have a hash index
+		return "hash"
+	}
+	return ""
+}
+
+func (fd *fieldDefinition) Complexity() int {
+	dir := fd.fieldDef.Directives.ForName(complexityDirective)
+	if dir == nil {
+		return 1
+	}
+	// This can't error as the value was validated during schema update.
+	cost, _ := strconv.Atoi(dir.Arguments.ForName(complexityValueArg).Value.Raw)
+	return cost
+}
+
+func (fd *fieldDefinition) RemoteResponseKey() string {
+	dir := fd.fieldDef.Directives.ForName(remoteResponseDirective)
+	if dir == nil {
+		return fd.Name()
+	}
+	// The name argument was validated to be non-empty during schema update.
+	return dir.Arguments.ForName(remoteResponseNameArg).Value.Raw
+}
+
 func (t *astType) Name() string {
 	if t.typ.NamedType == "" {
 		return t.typ.Elem.NamedType
@@ -2347,6 +3357,54 @@ func (t *astType) String() string {
 	return sb.String()
 }
 
+// ParseTypeString parses a type reference like `[Post!]!`, as returned by Type.String(), back
+// into an ast.Type.
+func ParseTypeString(s string) (*ast.Type, error) {
+	typ, rest, err := parseTypeRef(strings.TrimSpace(s))
+	if err != nil {
+		return nil, err
+	}
+	if rest != "" {
+		return nil, errors.Errorf("invalid type %q: unexpected %q after the type", s, rest)
+	}
+	return typ, nil
+}
+
+// parseTypeRef parses the type reference at the start of s and returns it along with the
+// remainder of s.
+func parseTypeRef(s string) (*ast.Type, string, error) {
+	typ := &ast.Type{}
+	if strings.HasPrefix(s, "[") {
+		elem, rest, err := parseTypeRef(strings.TrimSpace(s[1:]))
+		if err != nil {
+			return nil, "", err
+		}
+		if !strings.HasPrefix(rest, "]") {
+			return nil, "", errors.Errorf("invalid type %q: missing ]", s)
+		}
+		typ.Elem = elem
+		s = strings.TrimSpace(rest[1:])
+	} else {
+		end := strings.IndexFunc(s, func(r rune) bool {
+			return r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		if end == -1 {
+			end = len(s)
+		}
+		if end == 0 || unicode.IsDigit(rune(s[0])) {
+			return nil, "", errors.Errorf("invalid type %q: expected a type name", s)
+		}
+		typ.NamedType = s[:end]
+		s = strings.TrimSpace(s[end:])
+	}
+
+	if strings.HasPrefix(s, "!") {
+		typ.NonNull = true
+		s = strings.TrimSpace(s[1:])
+	}
+	return typ, s, nil
+}
+
 func (t *astType) IDField() FieldDefinition {
 	def := t.inSchema.schema.Types[t.Name()]
 	// If the field is of ID type but it is an external field,
@@ -2370,12 +3428,7 @@ func (t *astType) IDField() FieldDefinition {
 }
 
 func (t *astType) PasswordField() FieldDefinition {
-	def := t.inSchema.schema.Types[t.Name()]
-	if def.Kind != ast.Object && def.Kind != ast.Interface {
-		return nil
-	}
-
-	fd := getPasswordField(def)
+	fd := t.inSchema.passwordFields[t.Name()]
 	if fd == nil {
 		return nil
 	}
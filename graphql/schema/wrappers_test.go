@@ -1352,3 +1352,1340 @@ func TestParseSecrets(t *testing.T) {
 		})
 	}
 }
+
+func TestGeneratedOps(t *testing.T) {
+	schemaStr := `
+	type Post @generate(mutation: {delete: false}) {
+		id: ID!
+		title: String
+	}
+
+	type Author {
+		id: ID!
+		name: String
+	}`
+
+	schHandler, errs := NewHandler(schemaStr, false)
+	require.NoError(t, errs)
+	sch, err := FromString(schHandler.GQLSchema())
+	require.NoError(t, err)
+
+	post := &astType{
+		typ:      &ast.Type{NamedType: "Post"},
+		inSchema: sch.(*schema),
+	}
+	require.Equal(t, GenConfig{
+		GetQuery:       true,
+		FilterQuery:    true,
+		PasswordQuery:  true,
+		AggregateQuery: true,
+		AddMutation:    true,
+		UpdateMutation: true,
+		DeleteMutation: false,
+		Subscription:   false,
+	}, post.GeneratedOps())
+
+	author := &astType{
+		typ:      &ast.Type{NamedType: "Author"},
+		inSchema: sch.(*schema),
+	}
+	require.Equal(t, GenConfig{
+		GetQuery:       true,
+		FilterQuery:    true,
+		PasswordQuery:  true,
+		AggregateQuery: true,
+		AddMutation:    true,
+		UpdateMutation: true,
+		DeleteMutation: true,
+		Subscription:   false,
+	}, author.GeneratedOps())
+
+	// deletePost isn't generated at all, so it can't be listed
+	require.ElementsMatch(t, []string{"deleteAuthor"}, sch.Mutations(DeleteMutation))
+	require.ElementsMatch(t, []string{"addPost", "addAuthor"}, sch.Mutations(AddMutation))
+}
+
+func TestWalkSelections(t *testing.T) {
+	schemaStr := `
+	type Post {
+		id: ID!
+		title: String
+		author: Author
+	}
+
+	type Author {
+		id: ID!
+		name: String
+	}`
+
+	schHandler, errs := NewHandler(schemaStr, false)
+	require.NoError(t, errs)
+	sch, err := FromString(schHandler.GQLSchema())
+	require.NoError(t, err)
+
+	op, err := sch.Operation(&Request{
+		Query: `query {
+			queryPost {
+				title
+				writer: author {
+					name
+				}
+			}
+		}`,
+	})
+	require.NoError(t, err)
+	queries := op.Queries()
+	require.Len(t, queries, 1)
+
+	var paths []string
+	queries[0].WalkSelections(func(path string, f Field) {
+		paths = append(paths, path)
+	})
+	require.Equal(t, []string{"queryPost.title", "queryPost.writer", "queryPost.writer.name"},
+		paths)
+}
+
+func TestVectorFields(t *testing.T) {
+	sch := schemaFromString(t, `
+	type Product {
+		id: ID!
+		name: String
+		embedding: [Float!] @embedding(dimensions: 384)
+		ratings: [Float!]
+	}`)
+
+	typ := &astType{
+		typ:      &ast.Type{NamedType: "Product"},
+		inSchema: sch.(*schema),
+	}
+
+	embedding := typ.Field("embedding")
+	require.True(t, embedding.IsVector())
+	require.Equal(t, 384, embedding.VectorDimensions())
+
+	ratings := typ.Field("ratings")
+	require.False(t, ratings.IsVector())
+	require.Equal(t, 0, ratings.VectorDimensions())
+}
+
+func TestFieldTypeIsCached(t *testing.T) {
+	sch := schemaFromString(t, `
+	type Post {
+		id: ID!
+		title: String
+	}`)
+
+	query := `query { queryPost { id title } }`
+	op, err := sch.Operation(&Request{Query: query})
+	require.NoError(t, err)
+	q := op.Queries()[0]
+	require.Same(t, q.Type(), q.Type())
+	title := q.SelectionSet()[1]
+	require.Same(t, title.Type(), title.Type())
+
+	// A new operation gets new fields, so the cached types aren't shared across operations.
+	op2, err := sch.Operation(&Request{Query: query})
+	require.NoError(t, err)
+	require.NotSame(t, q.Type(), op2.Queries()[0].Type())
+}
+
+func BenchmarkFieldType(b *testing.B) {
+	h, err := NewHandler(`
+	type Author {
+		id: ID!
+		name: String! @search(by: [hash])
+		posts: [Post] @hasInverse(field: author)
+	}
+
+	type Post {
+		id: ID!
+		title: String! @search(by: [term])
+		text: String
+		author: Author
+	}`, false)
+	require.NoError(b, err)
+	sch, err := FromString(h.GQLSchema())
+	require.NoError(b, err)
+
+	op, err := sch.Operation(&Request{Query: `query {
+		queryAuthor(filter: { name: { eq: "A" } }) {
+			id
+			name
+			posts(first: 10) {
+				id
+				title
+				text
+				author { name }
+			}
+		}
+	}`})
+	require.NoError(b, err)
+
+	// The fields are collected once, as SelectionSet() returns new field wrappers, with nothing
+	// cached, every time it's called.
+	var fields []*field
+	var collect func(sel []Field)
+	collect = func(sel []Field) {
+		for _, f := range sel {
+			fields = append(fields, f.(*field))
+			collect(f.SelectionSet())
+		}
+	}
+	for _, q := range op.Queries() {
+		collect(q.SelectionSet())
+	}
+
+	// Each iteration starts with nothing cached, like a new operation, and rewriting calls Type()
+	// a few times for every field.
+	b.Run("cached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, f := range fields {
+				f.typ = nil
+				for j := 0; j < 5; j++ {
+					_ = f.Type().Name()
+				}
+			}
+		}
+	})
+	b.Run("uncached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, f := range fields {
+				for j := 0; j < 5; j++ {
+					f.typ = nil
+					_ = f.Type().Name()
+				}
+			}
+		}
+	})
+}
+
+func TestDetectInputCycles(t *testing.T) {
+	schHandler, errs := NewHandler(`
+	type Author {
+		id: ID!
+		name: String!
+		posts: [Post] @hasInverse(field: author)
+	}
+
+	type Post {
+		id: ID!
+		title: String!
+		author: Author!
+	}`, false)
+	require.NoError(t, errs)
+
+	// The generated inputs reference each other, but only via nullable fields, lists or IDs.
+	sch, err := FromString(schHandler.GQLSchema())
+	require.NoError(t, err)
+	require.Empty(t, sch.DetectInputCycles())
+
+	sch, err = FromString(schHandler.GQLSchema() + `
+	input NodeInput {
+		value: Int
+		next: NodeInput!
+	}
+
+	input LeftInput {
+		right: RightInput!
+	}
+
+	input RightInput {
+		left: LeftInput!
+		others: [LeftInput!]!
+	}`)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"LeftInput.right -> RightInput.left -> LeftInput",
+		"NodeInput.next -> NodeInput",
+	}, sch.DetectInputCycles())
+
+	// AInput is visited first and is done with CInput, before the cycle is entered from BInput.
+	sch, err = FromString(schHandler.GQLSchema() + `
+	input AInput {
+		c: CInput!
+	}
+
+	input BInput {
+		d: DInput!
+	}
+
+	input CInput {
+		value: Int
+	}
+
+	input DInput {
+		c: CInput!
+		b: BInput!
+	}`)
+	require.NoError(t, err)
+	require.Equal(t, []string{"BInput.d -> DInput.b -> BInput"}, sch.DetectInputCycles())
+}
+
+func TestIsArgListAtPath(t *testing.T) {
+	sch := schemaFromString(t, `
+	type Post {
+		id: ID!
+		title: String!
+		tags: [String]
+	}`)
+
+	op, err := sch.Operation(&Request{
+		Query: `mutation {
+			updatePost(input: {filter: {id: ["0x1"]}, set: {tags: ["a"]}}) {
+				numUids
+			}
+		}`,
+	})
+	require.NoError(t, err)
+	m := op.Mutations()[0]
+
+	tcases := []struct {
+		path   []string
+		isList bool
+	}{
+		{path: []string{"input"}, isList: false},
+		{path: []string{"input", "set"}, isList: false},
+		{path: []string{"input", "set", "tags"}, isList: true},
+		{path: []string{"input", "set", "title"}, isList: false},
+		{path: []string{"input", "filter", "id"}, isList: true},
+		{path: []string{"input", "set", "tags", "unknown"}, isList: false},
+		{path: []string{"input", "unknown"}, isList: false},
+		{path: []string{"unknown"}, isList: false},
+		{path: nil, isList: false},
+	}
+	for _, tcase := range tcases {
+		require.Equal(t, tcase.isList, m.IsArgListAtPath(tcase.path), "path: %v", tcase.path)
+	}
+}
+
+func TestMutationPayloadType(t *testing.T) {
+	sch := schemaFromString(t, `
+	type Post {
+		id: ID!
+		title: String
+	}`)
+
+	op, err := sch.Operation(&Request{
+		Query: `mutation {
+			addPost(input: [{title: "A"}]) { numUids }
+			deletePost(filter: {id: ["0x1"]}) { msg }
+		}`,
+	})
+	require.NoError(t, err)
+	mutations := op.Mutations()
+
+	require.Equal(t, "Post", mutations[0].MutatedType().Name())
+	require.Equal(t, "AddPostPayload", mutations[0].PayloadType().Name())
+	require.Equal(t, "Post", mutations[1].MutatedType().Name())
+	require.Equal(t, "DeletePostPayload", mutations[1].PayloadType().Name())
+}
+
+func TestImplementers(t *testing.T) {
+	sch := schemaFromString(t, `
+	interface Character {
+		id: ID!
+		name: String
+	}
+
+	type Human implements Character {
+		totalCredits: Int
+	}
+
+	type Droid implements Character @dgraph(type: "Robot") {
+		primaryFunction: String
+	}
+
+	type Starship {
+		id: ID!
+		name: String
+	}`)
+
+	require.Equal(t, []string{"Human", "Robot"}, sch.Implementers("Character"))
+	require.Empty(t, sch.Implementers("Starship"))
+	require.Empty(t, sch.Implementers("Unknown"))
+}
+
+func TestFieldErrorf(t *testing.T) {
+	sch := schemaFromString(t, `
+	type Post {
+		id: ID!
+		title: String
+	}`)
+
+	op, err := sch.Operation(&Request{
+		Query: `query {
+			getPost(id: "not-a-uid") {
+				title
+			}
+		}`,
+	})
+	require.NoError(t, err)
+	q := op.Queries()[0]
+
+	err = q.SelectionSet()[0].Errorf("bad %s", "title")
+	require.Equal(t, x.GqlErrorf("bad title").WithLocations(x.Location{Line: 3, Column: 5}), err)
+
+	_, _, err = q.IDArgValue()
+	require.Equal(t, x.GqlErrorf("ID argument (not-a-uid) of getPost was not able to be "+
+		"parsed").WithLocations(x.Location{Line: 2, Column: 4}), err)
+}
+
+func TestRemoteResponseKey(t *testing.T) {
+	sch := schemaFromString(t, `
+	type Country @remote {
+		code: String @remoteResponse(name: "country_code")
+		name: String
+	}
+
+	type Post {
+		id: ID!
+		title: String
+	}`)
+
+	typ := &astType{
+		typ:      &ast.Type{NamedType: "Country"},
+		inSchema: sch.(*schema),
+	}
+	require.Equal(t, "country_code", typ.Field("code").RemoteResponseKey())
+	require.Equal(t, "name", typ.Field("name").RemoteResponseKey())
+}
+
+func TestUpdateAndRemoveInputTypes(t *testing.T) {
+	sch := schemaFromString(t, `
+	type Post {
+		id: ID!
+		title: String
+		tags: [String]
+	}`)
+
+	op, err := sch.Operation(&Request{
+		Query: `mutation {
+			updatePost(input: {filter: {id: ["0x1"]}, set: {title: "A"}, remove: {tags: ["b"]}}) {
+				numUids
+			}
+			addPost(input: [{title: "A"}]) {
+				numUids
+			}
+		}`,
+	})
+	require.NoError(t, err)
+	mutations := op.Mutations()
+
+	update := mutations[0]
+	require.Equal(t, "PostPatch", update.UpdateInputType().Name())
+	require.Equal(t, "PostPatch", update.RemoveInputType().Name())
+	require.Equal(t, "String", update.UpdateInputType().Field("title").Type().Name())
+
+	add := mutations[1]
+	require.Nil(t, add.UpdateInputType())
+	require.Nil(t, add.RemoveInputType())
+}
+
+func TestRequiresUID(t *testing.T) {
+	sch := schemaFromString(t, `
+	interface Node {
+		id: ID!
+	}
+
+	type Author implements Node {
+		name: String
+	}
+
+	type Post implements Node {
+		title: String
+		author: Author
+		related: [Node]
+	}`)
+
+	op, err := sch.Operation(&Request{
+		Query: `query {
+			queryPost {
+				title
+				author { name }
+				related { id }
+			}
+		}`,
+	})
+	require.NoError(t, err)
+	q := op.Queries()[0]
+	require.True(t, q.RequiresUID())
+
+	fields := q.SelectionSet()
+	require.False(t, fields[0].RequiresUID())
+	require.True(t, fields[1].RequiresUID())
+	require.False(t, fields[1].SelectionSet()[0].RequiresUID())
+	require.True(t, fields[2].RequiresUID())
+	require.False(t, fields[2].SelectionSet()[0].RequiresUID())
+}
+
+func TestValidateCascadeFields(t *testing.T) {
+	sch := schemaFromString(t, `
+	type Post {
+		id: ID!
+		title: String
+		text: String
+	}`)
+
+	tcases := []struct {
+		name  string
+		query string
+		vars  map[string]interface{}
+		err   error
+	}{
+		{
+			name:  "cascade without fields",
+			query: `query { queryPost @cascade { title } }`,
+		},
+		{
+			name:  "cascade fields all selected",
+			query: `query { queryPost @cascade(fields: ["title", "text"]) { title heading: text } }`,
+		},
+		{
+			name:  "cascade field not selected",
+			query: `query { queryPost @cascade(fields: ["title", "text"]) { title } }`,
+			err: x.GqlErrorf("Field `text` given in @cascade is not selected in `queryPost`.").
+				WithLocations(x.Location{Line: 1, Column: 46}),
+		},
+		{
+			name:  "cascade fields from a variable all selected",
+			query: `query($f: [String]) { queryPost @cascade(fields: $f) { title text } }`,
+			vars:  map[string]interface{}{"f": []interface{}{"title", "text"}},
+		},
+		{
+			name:  "cascade field from a variable not selected",
+			query: `query($f: [String]) { queryPost @cascade(fields: $f) { title } }`,
+			vars:  map[string]interface{}{"f": []interface{}{"title", "text"}},
+			err: x.GqlErrorf("Field `text` given in @cascade is not selected in `queryPost`.").
+				WithLocations(x.Location{Line: 1, Column: 50}),
+		},
+		{
+			name:  "cascade field from a variable in the list not selected",
+			query: `query($f: String) { queryPost @cascade(fields: ["title", $f]) { title } }`,
+			vars:  map[string]interface{}{"f": "text"},
+			err: x.GqlErrorf("Field `text` given in @cascade is not selected in `queryPost`.").
+				WithLocations(x.Location{Line: 1, Column: 58}),
+		},
+	}
+
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			op, err := sch.Operation(&Request{Query: tcase.query, Variables: tcase.vars})
+			require.NoError(t, err)
+			err = op.Queries()[0].ValidateCascadeFields()
+			if tcase.err == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.Equal(t, tcase.err, err)
+		})
+	}
+}
+
+func TestPasswordPredicate(t *testing.T) {
+	sch := schemaFromString(t, `
+	type User @secret(field: "pwd") {
+		name: String! @id
+	}
+
+	type Admin @secret(field: "pin", pred: "admin_pin") {
+		name: String! @id
+	}
+
+	type Post {
+		id: ID!
+		title: String
+	}`)
+
+	require.Equal(t, "User.pwd", sch.PasswordPredicate("User"))
+	require.Equal(t, "admin_pin", sch.PasswordPredicate("Admin"))
+	require.Equal(t, "", sch.PasswordPredicate("Post"))
+	require.Equal(t, "", sch.PasswordPredicate("Unknown"))
+}
+
+func TestScalarLeaves(t *testing.T) {
+	sch := schemaFromString(t, `
+	enum Status {
+		DRAFT
+		PUBLISHED
+	}
+
+	type Author {
+		id: ID!
+		name: String
+	}
+
+	type Post {
+		id: ID!
+		title: String!
+		tags: [String]
+		status: Status
+		publishedAt: DateTime
+		author: Author
+		comments: [Comment]
+	}
+
+	type Comment {
+		id: ID!
+		text: String
+	}`)
+
+	typ := &astType{
+		typ:      &ast.Type{NamedType: "Post"},
+		inSchema: sch.(*schema),
+	}
+
+	var names []string
+	for _, fd := range typ.ScalarLeaves() {
+		names = append(names, fd.Name())
+	}
+	require.Equal(t, []string{"id", "title", "tags", "status", "publishedAt"}, names)
+}
+
+func TestIsLiveQueryable(t *testing.T) {
+	sch := schemaFromString(t, `
+	type Teacher @withSubscription {
+		tid: ID!
+		age: Int!
+		name: String @custom(http: {
+			url: "http://mock:8888/teacherName"
+			method: "POST"
+			body: "{tid: $tid}"
+			mode: SINGLE
+		})
+	}`)
+
+	tcases := []struct {
+		name  string
+		query string
+		err   error
+	}{
+		{
+			name:  "subscription without custom fields",
+			query: `subscription { queryTeacher { tid age } }`,
+		},
+		{
+			// aggregate queries are generated as subscriptions, and they are resolved by
+			// polling like any other query
+			name:  "aggregate subscription",
+			query: `subscription { aggregateTeacher { count ageMax } }`,
+		},
+		{
+			name:  "subscription with a custom field",
+			query: `subscription { getTeacher(tid: "0x1") { age name } }`,
+			err: x.GqlErrorf("Custom field `name` is not supported in graphql subscription").
+				WithLocations(x.Location{Line: 1, Column: 45}),
+		},
+		{
+			name:  "query",
+			query: `query { queryTeacher { age } }`,
+			err: x.GqlErrorf("Query `queryTeacher` is not part of a subscription.").
+				WithLocations(x.Location{Line: 1, Column: 9}),
+		},
+	}
+
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			op, err := sch.Operation(&Request{Query: tcase.query})
+			require.NoError(t, err)
+			ok, err := op.Queries()[0].IsLiveQueryable()
+			if tcase.err == nil {
+				require.NoError(t, err)
+				require.True(t, ok)
+				return
+			}
+			require.Equal(t, tcase.err, err)
+			require.False(t, ok)
+		})
+	}
+}
+
+func TestDgraphTypesToFetch(t *testing.T) {
+	sch := schemaFromString(t, `
+	interface Character {
+		id: ID!
+		name: String
+	}
+
+	interface Employee {
+		employeeId: String
+	}
+
+	type Human implements Character & Employee @dgraph(type: "Person") {
+		totalCredits: Int
+	}
+
+	type Droid implements Character {
+		primaryFunction: String
+	}`)
+
+	op, err := sch.Operation(&Request{
+		Query: `query {
+			queryHuman { name }
+			queryCharacter { name }
+		}`,
+	})
+	require.NoError(t, err)
+	queries := op.Queries()
+
+	require.Equal(t, []string{"Person", "Character", "Employee"},
+		queries[0].DgraphTypesToFetch())
+
+	types := queries[1].DgraphTypesToFetch()
+	require.Equal(t, "Character", types[0])
+	require.ElementsMatch(t, []string{"Character", "Person", "Employee", "Droid"}, types)
+}
+
+func TestIDIndexType(t *testing.T) {
+	sch := schemaFromString(t, `
+	type Author {
+		name: String! @id
+		bio: String
+	}
+
+	type Country {
+		code: String! @id(index: "exact") @search(by: [exact])
+	}`)
+
+	author := &astType{
+		typ:      &ast.Type{NamedType: "Author"},
+		inSchema: sch.(*schema),
+	}
+	country := &astType{
+		typ:      &ast.Type{NamedType: "Country"},
+		inSchema: sch.(*schema),
+	}
+
+	require.Equal(t, "hash", author.Field("name").IDIndexType())
+	require.Equal(t, "", author.Field("bio").IDIndexType())
+	require.Equal(t, "exact", country.Field("code").IDIndexType())
+}
+
+func TestInversePredicate(t *testing.T) {
+	sch := schemaFromString(t, `
+	type Author {
+		name: String! @id
+		posts: [Post] @hasInverse(field: author)
+	}
+
+	type Post {
+		id: ID!
+		title: String
+		author: Author @dgraph(pred: "writtenBy")
+	}`)
+
+	s := sch.(*schema)
+	author := &astType{
+		typ:             &ast.Type{NamedType: "Author"},
+		inSchema:        s,
+		dgraphPredicate: s.dgraphPredicate,
+	}
+	post := &astType{
+		typ:             &ast.Type{NamedType: "Post"},
+		inSchema:        s,
+		dgraphPredicate: s.dgraphPredicate,
+	}
+	require.Equal(t, "writtenBy", author.Field("posts").InversePredicate())
+	require.Equal(t, "", post.Field("title").InversePredicate())
+}
+
+func TestScalarKind(t *testing.T) {
+	sch := schemaFromString(t, `
+	type Post {
+		id: ID!
+		publishedAt: DateTime
+		views: Int64
+		location: Point
+		author: Author
+	}
+
+	type Author {
+		name: String! @id
+	}`)
+
+	post := &astType{
+		typ:      &ast.Type{NamedType: "Post"},
+		inSchema: sch.(*schema),
+	}
+	require.Equal(t, "DateTime", post.Field("publishedAt").Type().ScalarKind())
+	require.Equal(t, "Int64", post.Field("views").Type().ScalarKind())
+	require.Equal(t, "ID", post.Field("id").Type().ScalarKind())
+	require.Equal(t, "", post.Field("location").Type().ScalarKind())
+	require.Equal(t, "", post.Field("author").Type().ScalarKind())
+
+	// Custom scalars can't be declared in a schema, so they are never known to Dgraph.
+	json := &astType{
+		typ:      &ast.Type{NamedType: "JSON"},
+		inSchema: sch.(*schema),
+	}
+	require.Equal(t, "", json.ScalarKind())
+}
+
+func TestPasswordField(t *testing.T) {
+	sch := schemaFromString(t, `
+	type User @secret(field: "pwd") {
+		name: String! @id
+	}
+
+	type Post {
+		id: ID!
+		title: String
+	}`)
+
+	user := &astType{
+		typ:      &ast.Type{NamedType: "User"},
+		inSchema: sch.(*schema),
+	}
+	post := &astType{
+		typ:      &ast.Type{NamedType: "Post"},
+		inSchema: sch.(*schema),
+	}
+	require.Equal(t, "pwd", user.PasswordField().Name())
+	require.Equal(t, "Password", user.PasswordField().Type().Name())
+	require.Nil(t, post.PasswordField())
+}
+
+func BenchmarkPasswordField(b *testing.B) {
+	h, err := NewHandler(`
+	type User @secret(field: "pwd") {
+		name: String! @id
+		email: String @search(by: [hash])
+		age: Int
+	}`, false)
+	require.NoError(b, err)
+	sch, err := FromString(h.GQLSchema())
+	require.NoError(b, err)
+
+	user := &astType{
+		typ:      &ast.Type{NamedType: "User"},
+		inSchema: sch.(*schema),
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = user.PasswordField()
+	}
+}
+
+func TestIsTypename(t *testing.T) {
+	sch := schemaFromString(t, `
+	interface Character {
+		id: ID!
+		name: String
+	}
+
+	type Human implements Character {
+		height: Int
+	}
+
+	type Droid implements Character {
+		primaryFunction: String
+	}`)
+
+	op, err := sch.Operation(&Request{Query: `query {
+		queryCharacter {
+			kind: __typename
+			__typename
+			name
+			... on Human { height }
+		}
+	}`})
+	require.NoError(t, err)
+
+	sel := op.Queries()[0].SelectionSet()
+	require.Len(t, sel, 4)
+	kind, typename, name, height := sel[0], sel[1], sel[2], sel[3]
+
+	require.True(t, kind.IsTypename())
+	require.Equal(t, "kind", kind.ResponseName())
+	require.True(t, typename.IsTypename())
+	require.Equal(t, "__typename", typename.ResponseName())
+	require.False(t, name.IsTypename())
+
+	droid := []string{"Droid", "Character"}
+	require.True(t, kind.IncludeAbstractField(droid))
+	require.True(t, typename.IncludeAbstractField(droid))
+	require.True(t, name.IncludeAbstractField(droid))
+	require.False(t, height.IncludeAbstractField(droid))
+}
+
+func TestGraphQLTypeName(t *testing.T) {
+	sch := schemaFromString(t, `
+	interface Character @dgraph(type: "dgraph.character") {
+		id: ID!
+		name: String
+	}
+
+	type Human implements Character @dgraph(type: "dgraph.human") {
+		height: Int
+	}
+
+	type Post {
+		id: ID!
+		title: String
+	}`)
+
+	require.Equal(t, "Human", sch.GraphQLTypeName("dgraph.human"))
+	require.Equal(t, "Post", sch.GraphQLTypeName("Post"))
+	// Interfaces aren't object types.
+	require.Equal(t, "", sch.GraphQLTypeName("dgraph.character"))
+	require.Equal(t, "", sch.GraphQLTypeName("Human"))
+	require.Equal(t, "", sch.GraphQLTypeName("Unknown"))
+}
+
+func TestAbstractType(t *testing.T) {
+	sch := schemaFromString(t, `
+	interface Character {
+		id: ID!
+		name: String
+	}
+
+	type Human implements Character {
+		height: Int
+	}
+
+	type Droid implements Character {
+		primaryFunction: String
+	}
+
+	union Member = Human | Droid
+
+	type Team {
+		id: ID!
+		lead: Character
+		members: [Member]
+		droid: Droid
+	}`)
+
+	op, err := sch.Operation(&Request{Query: `query {
+		queryTeam {
+			lead { name }
+			members { ... on Human { height } }
+			droid { primaryFunction }
+		}
+	}`})
+	require.NoError(t, err)
+
+	sel := op.Queries()[0].SelectionSet()
+	require.True(t, sel[0].AbstractType(), "interface field")
+	require.True(t, sel[1].AbstractType(), "union field")
+	require.False(t, sel[2].AbstractType(), "object field")
+	require.False(t, op.Queries()[0].AbstractType())
+}
+
+func TestFingerprint(t *testing.T) {
+	sch1 := schemaFromString(t, `
+	type Author {
+		id: ID!
+		name: String! @search(by: [hash])
+		posts: [Post] @hasInverse(field: author)
+	}
+
+	type Post {
+		id: ID!
+		title: String @search(by: [term])
+		author: Author
+	}`)
+
+	sch2 := schemaFromString(t, `
+# The authors of posts.
+type Author { id: ID!, name: String! @search(by: [hash]),
+	posts: [Post] @hasInverse(field: author) }
+
+# Posts.
+type Post {
+	id: ID!
+	title: String  @search( by: [ term ] )
+	author: Author
+}`)
+
+	sch3 := schemaFromString(t, `
+	type Author {
+		id: ID!
+		name: String! @search(by: [hash])
+		posts: [Post] @hasInverse(field: author)
+	}
+
+	type Post {
+		id: ID!
+		title: String @search(by: [exact])
+		author: Author
+	}`)
+
+	require.Len(t, sch1.Fingerprint(), 64)
+	require.Equal(t, sch1.Fingerprint(), sch2.Fingerprint())
+	require.NotEqual(t, sch1.Fingerprint(), sch3.Fingerprint())
+}
+
+func TestParentDgraphType(t *testing.T) {
+	sch := schemaFromString(t, `
+	type Author @dgraph(type: "Writer") {
+		id: ID!
+		name: String
+		posts: [Post]
+	}
+
+	type Post {
+		id: ID!
+		title: String
+	}`)
+
+	op, err := sch.Operation(&Request{Query: `query {
+		queryAuthor {
+			name
+			posts { title }
+		}
+	}`})
+	require.NoError(t, err)
+
+	sel := op.Queries()[0].SelectionSet()
+	require.Equal(t, "Writer", sel[0].ParentDgraphType())
+	require.Equal(t, "Author", sel[0].GetObjectName())
+	require.Equal(t, "Post", sel[1].SelectionSet()[0].ParentDgraphType())
+}
+
+func TestMutatedTypes(t *testing.T) {
+	sch := schemaFromString(t, `
+	type Author {
+		id: ID!
+		name: String
+	}
+
+	type Post {
+		id: ID!
+		title: String
+	}
+
+	type Country @remote {
+		code: String
+	}`)
+
+	require.ElementsMatch(t, []string{"addAuthor", "addPost"}, sch.Mutations(AddMutation))
+	require.Equal(t, []string{"Author", "Post"}, sch.MutatedTypes())
+}
+
+func TestCanMergeWith(t *testing.T) {
+	sch := schemaFromString(t, `
+	type Post {
+		id: ID!
+		title: String
+		text: String
+	}`)
+
+	op, err := sch.Operation(&Request{Query: `query {
+		a: queryPost(first: 1) { title }
+		b: queryPost(first: 1) { text }
+		c: queryPost(first: 2) { title }
+		d: getPost(id: "0x1") { title }
+	}`})
+	require.NoError(t, err)
+
+	qs := op.Queries()
+	require.True(t, qs[0].CanMergeWith(qs[1]), "same name, arguments and type")
+	require.False(t, qs[0].CanMergeWith(qs[2]), "different arguments")
+	require.False(t, qs[0].CanMergeWith(qs[3]), "different names")
+
+	titles := []Field{qs[0].SelectionSet()[0], qs[2].SelectionSet()[0]}
+	require.True(t, titles[0].CanMergeWith(titles[1]))
+	require.False(t, titles[0].CanMergeWith(qs[1].SelectionSet()[0]))
+}
+
+func TestComplexity(t *testing.T) {
+	sch := schemaFromString(t, `
+	type Post {
+		id: ID!
+		title: String
+		comments: [Comment] @complexity(value: 5)
+		views: Int @complexity(value: 0)
+	}
+
+	type Comment {
+		id: ID!
+		text: String
+	}`)
+
+	post := &astType{
+		typ:      &ast.Type{NamedType: "Post"},
+		inSchema: sch.(*schema),
+	}
+	require.Equal(t, 5, post.Field("comments").Complexity())
+	require.Equal(t, 0, post.Field("views").Complexity())
+	require.Equal(t, 1, post.Field("title").Complexity())
+}
+
+func TestConcreteType(t *testing.T) {
+	sch := schemaFromString(t, `
+	interface Character {
+		id: ID!
+		name: String
+	}
+
+	type Human implements Character @dgraph(type: "Person") {
+		height: Int
+	}
+
+	type Droid implements Character {
+		primaryFunction: String
+	}`)
+
+	op, err := sch.Operation(&Request{Query: `query {
+		queryCharacter { name }
+	}`})
+	require.NoError(t, err)
+	q := op.Queries()[0]
+
+	human := q.ConcreteType([]interface{}{"Character", "Person"})
+	require.NotNil(t, human)
+	require.Equal(t, "Human", human.Name())
+	require.Equal(t, "Person.height", human.DgraphPredicate("height"))
+
+	droid := q.ConcreteType([]interface{}{"Droid", "Character"})
+	require.NotNil(t, droid)
+	require.Equal(t, "Droid", droid.Name())
+
+	require.Nil(t, q.ConcreteType([]interface{}{"Character"}))
+	require.Nil(t, q.ConcreteType(nil))
+}
+
+func TestXIDArgValue(t *testing.T) {
+	sch := schemaFromString(t, `
+	type Author {
+		id: ID!
+		name: String! @id
+	}
+
+	type Post {
+		id: ID!
+		title: String
+	}`)
+
+	op, err := sch.Operation(&Request{Query: `query {
+		getAuthor(name: "A") { name }
+		getPost(id: "0x1") { title }
+	}`})
+	require.NoError(t, err)
+
+	pred, val, err := op.Queries()[0].XIDArgValue()
+	require.NoError(t, err)
+	require.Equal(t, "Author.name", pred)
+	require.Equal(t, "A", val)
+
+	pred, val, err = op.Queries()[1].XIDArgValue()
+	require.NoError(t, err)
+	require.Empty(t, pred)
+	require.Empty(t, val)
+
+	// A node can also be looked up by its ID, even though its type has an @id field.
+	op, err = sch.Operation(&Request{Query: `query {
+		getAuthor(id: "0x1") { name }
+		getAuthor2: getAuthor { name }
+	}`})
+	require.NoError(t, err)
+	pred, val, err = op.Queries()[0].XIDArgValue()
+	require.NoError(t, err)
+	require.Empty(t, pred)
+	require.Empty(t, val)
+
+	_, _, err = op.Queries()[1].XIDArgValue()
+	require.Error(t, err)
+	require.Contains(t, err.Error(),
+		"getAuthor requires a value for either the ID field id or the @id field name of type "+
+			"Author")
+}
+
+func TestParseTypeString(t *testing.T) {
+	for _, str := range []string{"Int", "Post!", "[Post]", "[Post!]", "[Post]!", "[Post!]!",
+		"[[Float!]!]", "[[Float!]!]!"} {
+		typ, err := ParseTypeString(str)
+		require.NoError(t, err, str)
+		require.Equal(t, str, typ.String())
+		if strings.Count(str, "[") < 2 {
+			require.Equal(t, str, (&astType{typ: typ}).String())
+		}
+	}
+
+	typ, err := ParseTypeString(" [ Post ! ] ! ")
+	require.NoError(t, err)
+	require.Equal(t, "[Post!]!", typ.String())
+
+	for _, str := range []string{"", "!", "[Post", "Post]", "[]", "1Post", "Post!!"} {
+		_, err := ParseTypeString(str)
+		require.Error(t, err, str)
+	}
+}
+
+func TestOrderedArguments(t *testing.T) {
+	sch := schemaFromString(t, `
+	type Post {
+		id: ID!
+		title: String @search(by: [hash])
+	}`)
+
+	op, err := sch.Operation(&Request{
+		Query: `query($title: String) {
+			queryPost(offset: 2, filter: { title: { eq: $title } }, first: 1) { title }
+		}`,
+		Variables: map[string]interface{}{"title": "GraphQL"},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, []Argument{
+		{Name: "offset", Value: int64(2)},
+		{Name: "filter", Value: map[string]interface{}{
+			"title": map[string]interface{}{"eq": "GraphQL"}}},
+		{Name: "first", Value: int64(1)},
+	}, op.Queries()[0].OrderedArguments())
+}
+
+func TestDefaultSearchTokenizer(t *testing.T) {
+	sch := schemaFromString(t, `
+	type Post {
+		id: ID!
+		title: String @search
+		slug: String! @id
+		views: Int @search
+		published: DateTime @search
+		isPublic: Boolean @search
+		status: Status @search
+		author: Author
+	}
+
+	type Author {
+		id: ID!
+		name: String
+	}
+
+	enum Status {
+		DRAFT
+		PUBLISHED
+	}`)
+
+	post := &astType{
+		typ:      &ast.Type{NamedType: "Post"},
+		inSchema: sch.(*schema),
+	}
+	require.Equal(t, "term", post.Field("title").DefaultSearchTokenizer())
+	require.Equal(t, "hash", post.Field("slug").DefaultSearchTokenizer())
+	require.Equal(t, "int", post.Field("views").DefaultSearchTokenizer())
+	require.Equal(t, "year", post.Field("published").DefaultSearchTokenizer())
+	require.Equal(t, "bool", post.Field("isPublic").DefaultSearchTokenizer())
+	require.Equal(t, "hash", post.Field("status").DefaultSearchTokenizer())
+	require.Equal(t, "", post.Field("author").DefaultSearchTokenizer())
+}
+
+func TestEnumTypes(t *testing.T) {
+	sch := schemaFromString(t, `
+	type Post {
+		id: ID!
+		title: String
+		status: Status @search
+		tags: [Tag]
+	}
+
+	type Comment {
+		id: ID!
+		text: String
+	}
+
+	union Content = Post | Comment
+
+	enum Status {
+		DRAFT
+		PUBLISHED
+	}
+
+	enum Tag {
+		GO
+		GRAPHQL
+		DGRAPH
+	}`)
+
+	require.Equal(t, map[string][]string{
+		"Status": {"DRAFT", "PUBLISHED"},
+		"Tag":    {"GO", "GRAPHQL", "DGRAPH"},
+	}, sch.EnumTypes())
+}
+
+func TestFieldDefinitionForType(t *testing.T) {
+	sch := schemaFromString(t, `
+	interface Character {
+		id: ID!
+		name: String
+	}
+
+	type Human implements Character @dgraph(type: "Person") {
+		height: Int
+	}
+
+	type Droid implements Character {
+		primaryFunction: String
+	}`)
+
+	height := sch.FieldDefinitionForType("Person", "height")
+	require.NotNil(t, height)
+	require.Equal(t, "height", height.Name())
+	require.Equal(t, "Human", height.ParentType().Name())
+	require.Equal(t, "Person.height", height.DgraphPredicate())
+
+	name := sch.FieldDefinitionForType("Droid", "name")
+	require.NotNil(t, name)
+	require.Equal(t, "Character.name", name.DgraphPredicate())
+
+	require.Nil(t, sch.FieldDefinitionForType("Droid", "height"))
+	require.Nil(t, sch.FieldDefinitionForType("Character", "height"))
+	require.Nil(t, sch.FieldDefinitionForType("Human", "height"))
+}
+
+func TestReverseEdges(t *testing.T) {
+	sch := schemaFromString(t, `
+	type Author {
+		id: ID!
+		name: String
+		posts: [Post] @dgraph(pred: "~posts")
+	}
+
+	type Post {
+		id: ID!
+		title: String
+		author: Author @dgraph(pred: "posts")
+		editors: [Editor] @dgraph(pred: "~edits.post")
+	}
+
+	type Editor {
+		id: ID!
+		edits: [Post] @dgraph(pred: "edits.post")
+	}`)
+
+	require.Equal(t, map[string]string{
+		"~posts":      "posts",
+		"~edits.post": "edits.post",
+	}, sch.ReverseEdges())
+}
+
+func TestHasSelections(t *testing.T) {
+	sch := schemaFromString(t, `
+	type Post {
+		id: ID!
+		title: String
+		author: Author
+	}
+
+	type Author {
+		id: ID!
+		name: String
+	}`)
+
+	op, err := sch.Operation(&Request{Query: `query {
+		queryPost {
+			title
+			author { name }
+		}
+	}`})
+	require.NoError(t, err)
+
+	q := op.Queries()[0]
+	require.True(t, q.HasSelections())
+	require.False(t, q.SelectionSet()[0].HasSelections(), "leaf field")
+	require.True(t, q.SelectionSet()[1].HasSelections(), "object field")
+}